@@ -1,8 +1,10 @@
 package wlog
 
 import (
+	"math"
 	"sync"
 	"strconv"
+	"time"
 )
 
 var bufferPool = sync.Pool{
@@ -141,6 +143,11 @@ func (b *Buffer) AppendComplex128(v complex128) {
 	r, i := float64(real(val)), float64(imag(val))
 	b.AppendByte('(')
 	b.AppendFloat64(r)
+	// The sign of the imaginary part is always required,
+	// but strconv only formats the sign of negative numbers and "+Inf".
+	if math.IsNaN(i) || (!math.Signbit(i) && !math.IsInf(i, 1)) {
+		b.AppendByte('+')
+	}
 	b.AppendFloat64(i)
 	b.AppendString("i)")
 }
@@ -155,13 +162,7 @@ func (b *Buffer) AppendBytes(v []byte) {
 	b.buf = append(b.buf, v...)
 }
 
-// AppendBytes appends a byte slice to the buffer.
-//func (b *Buffer) AppendTime(v int64) {
-//	//b.AppendInt64(v)
-//	t:=time.Unix(0,v)
-//	b.buf = t.AppendFormat(b.buf,"2016-01-02 15:04:05.000")
-//}
-//
-//func (b *Buffer) AppendTimeNew(v time.Time) {
-//	b.buf = v.AppendFormat(b.buf,"2016-01-02 15:04:05.000")
-//}
\ No newline at end of file
+// AppendTime appends a string representation of the time formatted by the layout to the buffer.
+func (b *Buffer) AppendTime(v time.Time, layout string) {
+	b.buf = v.AppendFormat(b.buf, layout)
+}
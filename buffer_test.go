@@ -0,0 +1,28 @@
+package wlog
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBufferAppendComplex128(t *testing.T) {
+	tests := []struct {
+		val      complex128
+		expected string
+	}{
+		{complex(1, 2), "(1+2i)"},
+		{complex(1, -2), "(1-2i)"},
+		{complex(-1.5, 0), "(-1.5+0i)"},
+		{complex(0, math.Inf(1)), "(0+Infi)"},
+		{complex(0, math.Inf(-1)), "(0-Infi)"},
+		{complex(0, math.NaN()), "(0+NaNi)"},
+	}
+	for _, tt := range tests {
+		buf := GetBuf()
+		buf.AppendComplex128(tt.val)
+		assert.Equal(t, tt.expected, buf.String())
+		PutBuf(buf)
+	}
+}
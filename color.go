@@ -9,8 +9,13 @@ const (
 	Green
 	Yellow
 	Blue
+	Magenta
+	Cyan
 )
 
+// colorReset is the escape sequence to reset the color.
+const colorReset = "\x1b[0m"
+
 // Color is the type defined for level prefix color.
 type Color uint8
 
@@ -18,3 +23,10 @@ type Color uint8
 func (c Color) With(str string) string {
 	return fmt.Sprintf("\x1b[%dm%s\x1b[0m", uint8(c), str)
 }
+
+// appendStart appends the escape sequence to start the color to the buf.
+func (c Color) appendStart(buf *Buffer) {
+	buf.AppendString("\x1b[")
+	buf.AppendUint8(uint8(c))
+	buf.AppendByte('m')
+}
@@ -1,6 +1,8 @@
 package wlog
 
 import (
+	"errors"
+	"fmt"
 	"os"
 	"time"
 	"io"
@@ -56,9 +58,13 @@ type Config struct {
 	// MinLevel is the string representation of minimum logging level.
 	// it's default value is "debug", and supported values are as follow:
 	// "debug", "info", "warn", "error", "fatal", "panic".
+	// The value is case-insensitive and "warning" is an alias of "warn".
 	MinLevel string `json:"min_level" yaml:"min_level"`
 	// Encoder is the type of chosen encoder, it's default value is "text",
-	// and temporarily supported values are as follow: "text", "json".
+	// and temporarily supported values are as follow: "text", "json", "logfmt", "console", "pattern".
+	//
+	// The color of "console" is enabled automatically if all paths are terminals,
+	// and the pattern of "pattern" is specified by the EncoderConfig's Pattern.
 	Encoder       string        `json:"encoder" yaml:"encoder"`
 	EncoderConfig EncoderConfig `json:"encoder_config" yaml:"encoder_config"`
 	// Paths is the descriptor of standard output, standard error or file paths
@@ -69,9 +75,30 @@ type Config struct {
 	Paths        []string     `json:"paths" yaml:"paths"`
 	FileConfig   FileConfig   `json:"file_config" yaml:"file_config"`
 	WriterConfig WriterConfig `json:"writer_config" yaml:"writer_config"`
+	// PathMinLevels is the string representation of minimum logging level of every path in Paths,
+	// the supported values are the same as the MinLevel.
+	//
+	// The logs below the level of a path are not written to the path,
+	// and all paths without a specified level accept all logs allowed by the MinLevel.
+	PathMinLevels map[string]string `json:"path_min_levels" yaml:"path_min_levels"`
+	// PathEncoders is the encoder of every path in Paths.
+	//
+	// All paths without a specified encoder use the Encoder and EncoderConfig.
+	PathEncoders map[string]PathEncoderConfig `json:"path_encoders" yaml:"path_encoders"`
 	// ErrWriter is the Path of writer to write internal errors to.
 	// A standard error is the default writer.
 	ErrWriter string `json:"err_writer" yaml:"err_writer"`
+	// CallerEnabled indicates whether to record the caller of logging methods.
+	CallerEnabled bool `json:"caller_enabled" yaml:"caller_enabled"`
+	// CallerSkip is the number of additional stack frames to skip when recording the caller.
+	CallerSkip int `json:"caller_skip" yaml:"caller_skip"`
+}
+
+// PathEncoderConfig is the encoder config of a specified path.
+type PathEncoderConfig struct {
+	// Encoder is the type of chosen encoder, the supported values are the same as the Config's Encoder.
+	Encoder       string        `json:"encoder" yaml:"encoder"`
+	EncoderConfig EncoderConfig `json:"encoder_config" yaml:"encoder_config"`
 }
 
 type EncoderConfig struct {
@@ -79,6 +106,30 @@ type EncoderConfig struct {
 	LevelLower   bool   `json:"level_lower" yaml:"level_lower"`
 	TimeDisabled bool   `json:"time_disable" yaml:"time_disable"`
 	LineEnding   string `json:"line_ending" yaml:"line_ending"`
+	// CallerEncoder is the type of chosen caller encoder, it's default value is "short",
+	// and supported values are as follow: "short", "full", "func".
+	CallerEncoder string `json:"caller_encoder" yaml:"caller_encoder"`
+	// Pattern is the pattern of the "pattern" encoder, such as "%d{15:04:05.000} %-5p %m %fields%n",
+	// the default pattern is "%d %p %m %fields%n".
+	Pattern string `json:"pattern" yaml:"pattern"`
+	// LevelKey, TimeKey and MsgKey are the keys of the level, time and message of every log,
+	// their default values are "level", "time" and "msg", and the corresponding part is omitted
+	// if its key is "-".
+	LevelKey string `json:"level_key" yaml:"level_key"`
+	TimeKey  string `json:"time_key" yaml:"time_key"`
+	MsgKey   string `json:"msg_key" yaml:"msg_key"`
+	// TimeEncoder is the type of chosen time encoder, the default time encoder of the encoder is
+	// used if it's empty, and supported values are as follow: "rfc3339", "rfc3339nano",
+	// "epoch", "epoch_millis", "epoch_micros", "epoch_nanos", "layout".
+	TimeEncoder string `json:"time_encoder" yaml:"time_encoder"`
+	// TimeLayout is the layout of the "layout" time encoder, such as "2006-01-02 15:04:05".
+	TimeLayout string `json:"time_layout" yaml:"time_layout"`
+	// TimeUTC indicates whether to convert the time to UTC before encoding it.
+	// It only works if the TimeEncoder is not empty.
+	TimeUTC bool `json:"time_utc" yaml:"time_utc"`
+	// DurationEncoder is the type of chosen duration encoder, it's default value is "string",
+	// and supported values are as follow: "string", "nanos", "millis", "seconds".
+	DurationEncoder string `json:"duration_encoder" yaml:"duration_encoder"`
 }
 
 type FileConfig struct {
@@ -148,15 +199,23 @@ func NewMultiWriterConfig(paths ...string) Config {
 
 // Create returns a Logger form the config and the opts.
 func (c Config) Create(opts ...LoggerOpt) (*Logger, error) {
+	lvl, err := c.CreateLevel()
+	if err != nil {
+		return nil, err
+	}
 	errW, err := c.CreateErrWriter()
 	if err != nil {
 		return nil, err
 	}
-	encoder := c.CreateEncoder()
-	writer := c.CreateWriter(SetFileErrW(errW))
-	writer = c.WrapWriter(writer, SetBufErrW(errW))
-	h := NewBaseHandler(writer, encoder)
-	logger := NewLogger(h, []LoggerOpt{SetLogMinLvl(c.CreateLevel()), SetLogErrW(errW)}...)
+	h, err := c.CreateHandler(errW)
+	if err != nil {
+		return nil, err
+	}
+	cfgOpts := []LoggerOpt{SetLogMinLvl(lvl), SetLogErrW(errW)}
+	if c.CallerEnabled {
+		cfgOpts = append(cfgOpts, EnableLogCaller(), AddLogCallerSkip(c.CallerSkip))
+	}
+	logger := NewLogger(h, cfgOpts...)
 	if len(opts) == 0 {
 		return logger, nil
 	}
@@ -172,8 +231,91 @@ func (c Config) CreateErrWriter() (io.Writer, error) {
 	return w, err
 }
 
+// CreateHandler returns a Handler form the config,
+// the given errW is used to output the internal errors of writers.
+//
+// If any path has its own minimum logging level or encoder, every path is written by
+// an independent Handler and all Handlers are combined by a TeeHandler,
+// otherwise all paths share the same Handler.
+func (c Config) CreateHandler(errW io.Writer) (Handler, error) {
+	encoder, err := c.BuildEncoder()
+	if err != nil {
+		return nil, err
+	}
+	if len(c.PathMinLevels) == 0 && len(c.PathEncoders) == 0 {
+		writer := c.CreateWriter(SetFileErrW(errW))
+		return NewBaseHandler(c.WrapWriter(writer, SetBufErrW(errW)), encoder), nil
+	}
+	paths := c.paths()
+	// Parse all levels before creating any writer.
+	lvls := make(map[string]Level, len(c.PathMinLevels))
+	for path, str := range c.PathMinLevels {
+		if !containsStr(paths, path) {
+			return nil, fmt.Errorf("Config: the path %q of path_min_levels is not in paths", path)
+		}
+		lvl, err := ParseLevel(str)
+		if err != nil {
+			return nil, err
+		}
+		lvls[path] = lvl
+	}
+	for path := range c.PathEncoders {
+		if !containsStr(paths, path) {
+			return nil, fmt.Errorf("Config: the path %q of path_encoders is not in paths", path)
+		}
+	}
+	// Create all encoders before creating any writer.
+	encoders := make(map[string]Encoder, len(paths))
+	for _, path := range paths {
+		pathEncoder, err := c.createPathEncoder(path, encoder)
+		if err != nil {
+			return nil, err
+		}
+		encoders[path] = pathEncoder
+	}
+	fileOpts := c.fileWriterOpts(SetFileErrW(errW))
+	hs := make([]Handler, 0, len(paths))
+	for _, path := range paths {
+		writer := c.WrapWriter(createPathWriter(path, fileOpts...), SetBufErrW(errW))
+		var h Handler = NewBaseHandler(writer, encoders[path])
+		if lvl, ok := lvls[path]; ok {
+			h = NewLevelHandler(h, lvl)
+		}
+		hs = append(hs, h)
+	}
+	if len(hs) == 1 {
+		return hs[0], nil
+	}
+	return NewTeeHandler(hs...), nil
+}
+
+// createPathEncoder returns the Encoder of the given path,
+// or the given defaultEncoder if the path has no specified encoder.
+func (c Config) createPathEncoder(path string, defaultEncoder Encoder) (Encoder, error) {
+	pc, ok := c.PathEncoders[path]
+	if !ok {
+		return defaultEncoder, nil
+	}
+	c.Encoder = pc.Encoder
+	c.EncoderConfig = pc.EncoderConfig
+	c.Paths = []string{path}
+	return c.BuildEncoder()
+}
+
 // CreateEncoder returns a Encoder form the config and the opts.
+// It panics if the config of the encoder is invalid, use the BuildEncoder to get the error instead.
 func (c Config) CreateEncoder(opts ...EncoderOpt) Encoder {
+	encoder, err := c.BuildEncoder(opts...)
+	if err != nil {
+		panic(err)
+	}
+	return encoder
+}
+
+// BuildEncoder returns a Encoder form the config and the opts.
+// It returns an error if the caller encoder, time encoder, duration encoder
+// or the pattern of the "pattern" encoder is invalid.
+func (c Config) BuildEncoder(opts ...EncoderOpt) (Encoder, error) {
 	ec := c.EncoderConfig
 	cfgOpts := []EncoderOpt{SetLineEnding(ec.LineEnding)}
 	if ec.ColorEnabled {
@@ -185,23 +327,142 @@ func (c Config) CreateEncoder(opts ...EncoderOpt) Encoder {
 	if ec.TimeDisabled {
 		cfgOpts = append(cfgOpts, DisableTime())
 	}
+	if ec.LevelKey != "" {
+		cfgOpts = append(cfgOpts, SetLevelKey(cfgKey(ec.LevelKey)))
+	}
+	if ec.TimeKey != "" {
+		cfgOpts = append(cfgOpts, SetTimeKey(cfgKey(ec.TimeKey)))
+	}
+	if ec.MsgKey != "" {
+		cfgOpts = append(cfgOpts, SetMsgKey(cfgKey(ec.MsgKey)))
+	}
+	if ec.TimeEncoder != "" {
+		te, err := ec.createTimeEncoder()
+		if err != nil {
+			return nil, err
+		}
+		cfgOpts = append(cfgOpts, SetTimeEncoder(te))
+	}
+	switch ec.DurationEncoder {
+	case "", "string":
+	case "nanos":
+		cfgOpts = append(cfgOpts, SetDurationEncoder(&NanosDurationEncoder{}))
+	case "millis":
+		cfgOpts = append(cfgOpts, SetDurationEncoder(&MillisDurationEncoder{}))
+	case "seconds":
+		cfgOpts = append(cfgOpts, SetDurationEncoder(&SecondsDurationEncoder{}))
+	default:
+		return nil, fmt.Errorf("Config: unknown duration encoder %q", ec.DurationEncoder)
+	}
+	switch ec.CallerEncoder {
+	case "", "short":
+	case "full":
+		cfgOpts = append(cfgOpts, SetCallerEncoder(&FullCallerEncoder{}))
+	case "func":
+		cfgOpts = append(cfgOpts, SetCallerEncoder(&FuncCallerEncoder{}))
+	default:
+		return nil, fmt.Errorf("Config: unknown caller encoder %q", ec.CallerEncoder)
+	}
+	if c.Encoder == "console" {
+		// Disable the color if any path is not a terminal.
+		cfgOpts = append(cfgOpts, EnableTerminalColor(c.pathWriters()...))
+	}
 	opts = append(cfgOpts, opts...)
 	var encoder Encoder
 	switch c.Encoder {
 	case "json":
 		encoder = NewJsonEncoder(opts...)
+	case "logfmt":
+		encoder = NewLogfmtEncoder(opts...)
+	case "console":
+		encoder = NewConsoleEncoder(opts...)
+	case "pattern":
+		return NewPatternEncoder(ec.Pattern, opts...)
 	default:
 		encoder = NewTextEncoder(opts...)
 	}
-	return encoder
+	return encoder, nil
+}
+
+// createTimeEncoder returns a TimeEncoder form the config.
+func (ec EncoderConfig) createTimeEncoder() (TimeEncoder, error) {
+	var te TimeEncoder
+	switch ec.TimeEncoder {
+	case "rfc3339":
+		te = &RFC3339TimeEncoder{}
+	case "rfc3339nano":
+		te = &RFC3339NanoTimeEncoder{}
+	case "epoch":
+		te = &EpochTimeEncoder{Unit: time.Second}
+	case "epoch_millis":
+		te = &EpochTimeEncoder{Unit: time.Millisecond}
+	case "epoch_micros":
+		te = &EpochTimeEncoder{Unit: time.Microsecond}
+	case "epoch_nanos":
+		te = &EpochTimeEncoder{Unit: time.Nanosecond}
+	case "layout":
+		if ec.TimeLayout == "" {
+			return nil, errors.New("Config: the time_layout is required by the layout time encoder")
+		}
+		te = LayoutTimeEncoder{Layout: ec.TimeLayout}
+	default:
+		return nil, fmt.Errorf("Config: unknown time encoder %q", ec.TimeEncoder)
+	}
+	if ec.TimeUTC {
+		te = &UTCTimeEncoder{Inner: te}
+	}
+	return te, nil
+}
+
+// cfgKey returns the key to be set by the EncoderOpt, "-" is converted to
+// an empty string to omit the corresponding part.
+func cfgKey(key string) string {
+	if key == "-" {
+		return ""
+	}
+	return key
 }
 
 // CreateWriter returns a Writer form the config and the opts.
 func (c Config) CreateWriter(opts ...FileWriterOpt) Writer {
+	var writers []Writer
+	opts = c.fileWriterOpts(opts...)
+	for _, path := range c.paths() {
+		writers = append(writers, createPathWriter(path, opts...))
+	}
+	if len(writers) == 1 {
+		return writers[0]
+	}
+	return NewMultiWriter(writers)
+}
+
+// paths returns the Paths of the config, or "stdout" if the length of Paths is 0.
+func (c Config) paths() []string {
 	if len(c.Paths) == 0 {
-		c.Paths = []string{"stdout"}
+		return []string{"stdout"}
 	}
-	var writers []Writer
+	return c.Paths
+}
+
+// pathWriters returns the standard output and standard error in the paths of the config,
+// or nil if any path is a file.
+func (c Config) pathWriters() []io.Writer {
+	var ws []io.Writer
+	for _, path := range c.paths() {
+		switch path {
+		case "stdout":
+			ws = append(ws, os.Stdout)
+		case "stderr":
+			ws = append(ws, os.Stderr)
+		default:
+			return nil
+		}
+	}
+	return ws
+}
+
+// fileWriterOpts returns the FileWriterOpts form the config and the opts.
+func (c Config) fileWriterOpts(opts ...FileWriterOpt) []FileWriterOpt {
 	fc := c.FileConfig
 	cfgOpts := []FileWriterOpt{SetFileMaxSize(fc.MaxSize),
 		SetFileMaxRotatedSize(fc.MaxRotatedSize),
@@ -209,21 +470,28 @@ func (c Config) CreateWriter(opts ...FileWriterOpt) Writer {
 	if fc.DisableDaily {
 		cfgOpts = append(cfgOpts, DisableFileDaily())
 	}
-	opts = append(cfgOpts, opts...)
-	for _, path := range c.Paths {
-		switch path {
-		case "stdout":
-			writers = append(writers, NewIOWriter(os.Stdout))
-		case "stderr":
-			writers = append(writers, NewIOWriter(os.Stderr))
-		default:
-			writers = append(writers, NewFileWriter(path, opts...))
-		}
+	return append(cfgOpts, opts...)
+}
+
+// createPathWriter returns a Writer to write logs to the given path.
+func createPathWriter(path string, opts ...FileWriterOpt) Writer {
+	switch path {
+	case "stdout":
+		return NewIOWriter(os.Stdout)
+	case "stderr":
+		return NewIOWriter(os.Stderr)
+	default:
+		return NewFileWriter(path, opts...)
 	}
-	if len(writers) == 1 {
-		return writers[0]
+}
+
+func containsStr(strs []string, str string) bool {
+	for _, s := range strs {
+		if s == str {
+			return true
+		}
 	}
-	return NewMultiWriter(writers)
+	return false
 }
 
 // WrapWriter wraps the given Writer form the config and the opts.
@@ -236,22 +504,11 @@ func (c Config) WrapWriter(inner Writer, opts ...BufWriterOpt) *TimingFlushWrite
 	return NewTimingFlushWriter(bw, time.Duration(wc.FlushInterval)*time.Second)
 }
 
-// CreateWriter returns a Level form the config.
-func (c Config) CreateLevel() Level {
-	var lvl Level
-	switch c.MinLevel {
-	case "info":
-		lvl = DebugLvl
-	case "warn":
-		lvl = DebugLvl
-	case "error":
-		lvl = DebugLvl
-	case "fatal":
-		lvl = DebugLvl
-	case "panic":
-		lvl = DebugLvl
-	default:
-		lvl = DebugLvl
+// CreateLevel returns a Level form the config.
+// It returns an error if the MinLevel is not a supported level.
+func (c Config) CreateLevel() (Level, error) {
+	if c.MinLevel == "" {
+		return DebugLvl, nil
 	}
-	return lvl
+	return ParseLevel(c.MinLevel)
 }
@@ -0,0 +1,45 @@
+package wlog
+
+import "context"
+
+// ContextExtractor extracts the request-scoped fields such as the request ID from the ctx.
+type ContextExtractor func(ctx context.Context) []Field
+
+type loggerCtxKey struct{}
+
+// AddLogContextExtractors adds the extractors to extract the fields from the context
+// when logging a message by the methods such as InfoCtx.
+func AddLogContextExtractors(extractors ...ContextExtractor) LoggerOpt {
+	return func(l *Logger) {
+		// Never append to the extractors shared with the Logger to be cloned.
+		all := make([]ContextExtractor, 0, len(l.ctxExtractors)+len(extractors))
+		all = append(all, l.ctxExtractors...)
+		l.ctxExtractors = append(all, extractors...)
+	}
+}
+
+// WithContext returns a copy of the ctx that carries l.
+func (l *Logger) WithContext(ctx context.Context) context.Context {
+	return context.WithValue(ctx, loggerCtxKey{}, l)
+}
+
+// FromContext returns the Logger carried by the ctx,
+// or the global logger if the ctx carries no Logger.
+func FromContext(ctx context.Context) *Logger {
+	if l, ok := ctx.Value(loggerCtxKey{}).(*Logger); ok {
+		return l
+	}
+	return globalLogger
+}
+
+// extractCtxFields returns the fields extracted from the ctx followed by the given fields.
+func (l *Logger) extractCtxFields(ctx context.Context, fields []Field) []Field {
+	var all []Field
+	for _, extract := range l.ctxExtractors {
+		all = append(all, extract(ctx)...)
+	}
+	if len(all) == 0 {
+		return fields
+	}
+	return append(all, fields...)
+}
@@ -50,6 +50,20 @@ type ObjEncoder interface {
 	AppendArray(buf *Buffer, val ArrayEncoder)
 
 	AppendObject(buf *Buffer, val FieldVal)
+
+	// AppendError appends the val, it's never nil.
+	AppendError(buf *Buffer, val error)
+
+	// AppendJSON appends the val that is a valid and compact JSON.
+	AppendJSON(buf *Buffer, val []byte)
+
+	// AppendObjectStart appends the beginning of an object.
+	AppendObjectStart(buf *Buffer)
+	// AppendObjectKey appends the key of the i-th key-value pair of an object,
+	// including the separator before it if the i is greater than 0.
+	AppendObjectKey(buf *Buffer, key string, i int)
+	// AppendObjectEnd appends the end of an object.
+	AppendObjectEnd(buf *Buffer)
 }
 
 type ArrayEncoder interface {
@@ -128,24 +142,130 @@ func appendFastTime(buf *Buffer, t time.Time, milliEnabled bool) {
 	buf.AppendInt(ms)
 }
 
+// ShortTimeEncoder encodes a time.Time in the form of "15:04:05.000".
+type ShortTimeEncoder struct{}
+
+func (e *ShortTimeEncoder) Append(buf *Buffer, enc ObjEncoder, t time.Time) {
+	LayoutTimeEncoder{Layout: "15:04:05.000"}.Append(buf, enc, t)
+}
+
+// LayoutTimeEncoder encodes a time.Time as a string formatted by the Layout.
 type LayoutTimeEncoder struct {
 	Layout string
 }
 
-func (e LayoutTimeEncoder) AppendTime(buf *Buffer, enc ObjEncoder, t time.Time) {
-	enc.AppendString(buf, t.Format(e.Layout))
+func (e LayoutTimeEncoder) Append(buf *Buffer, enc ObjEncoder, t time.Time) {
+	tmp := GetBuf()
+	tmp.AppendTime(t, e.Layout)
+	enc.AppendByteString(buf, tmp.Bytes())
+	PutBuf(tmp)
+}
+
+// RFC3339TimeEncoder encodes a time.Time as a string in the form of "2006-01-02T15:04:05Z07:00".
+type RFC3339TimeEncoder struct{}
+
+func (e *RFC3339TimeEncoder) Append(buf *Buffer, enc ObjEncoder, t time.Time) {
+	LayoutTimeEncoder{Layout: time.RFC3339}.Append(buf, enc, t)
+}
+
+// RFC3339NanoTimeEncoder encodes a time.Time as a string in the form of "2006-01-02T15:04:05.999999999Z07:00".
+type RFC3339NanoTimeEncoder struct{}
+
+func (e *RFC3339NanoTimeEncoder) Append(buf *Buffer, enc ObjEncoder, t time.Time) {
+	LayoutTimeEncoder{Layout: time.RFC3339Nano}.Append(buf, enc, t)
+}
+
+// EpochTimeEncoder encodes a time.Time as an integer of the elapsed Units since the Unix epoch,
+// such as time.Second, time.Millisecond, time.Microsecond, time.Nanosecond or time.Minute.
+// If the Unit is not positive, time.Second is used.
+type EpochTimeEncoder struct {
+	Unit time.Duration
+}
+
+func (e *EpochTimeEncoder) Append(buf *Buffer, enc ObjEncoder, t time.Time) {
+	switch {
+	case e.Unit <= 0 || e.Unit == time.Second:
+		// Avoid the overflow of the UnixNano for the time that is far away from now.
+		enc.AppendInt64(buf, t.Unix())
+	case e.Unit == time.Nanosecond:
+		enc.AppendInt64(buf, t.UnixNano())
+	case e.Unit%time.Second == 0:
+		// The Unit is a multiple of a second, such as time.Minute.
+		enc.AppendInt64(buf, t.Unix()/int64(e.Unit/time.Second))
+	case time.Second%e.Unit == 0:
+		// The Unit is a fraction of a second, such as time.Millisecond.
+		enc.AppendInt64(buf, t.Unix()*int64(time.Second/e.Unit)+int64(t.Nanosecond())/int64(e.Unit))
+	default:
+		enc.AppendInt64(buf, t.UnixNano()/int64(e.Unit))
+	}
+}
+
+// UTCTimeEncoder converts a time.Time to UTC before encoding it by the Inner.
+type UTCTimeEncoder struct {
+	Inner TimeEncoder
+}
+
+func (e *UTCTimeEncoder) Append(buf *Buffer, enc ObjEncoder, t time.Time) {
+	e.Inner.Append(buf, enc, t.UTC())
 }
 
 type DurationEncoder interface {
 	Append(buf *Buffer, enc ObjEncoder, dur time.Duration)
 }
 
+// StrDurationEncoder encodes a time.Duration as a string such as "1m30s".
 type StrDurationEncoder struct{}
 
 func (e *StrDurationEncoder) Append(buf *Buffer, enc ObjEncoder, dur time.Duration) {
 	enc.AppendString(buf, dur.String())
 }
 
+// NanosDurationEncoder encodes a time.Duration as an integer of nanoseconds.
+type NanosDurationEncoder struct{}
+
+func (e *NanosDurationEncoder) Append(buf *Buffer, enc ObjEncoder, dur time.Duration) {
+	enc.AppendInt64(buf, int64(dur))
+}
+
+// MillisDurationEncoder encodes a time.Duration as a floating-point number of milliseconds.
+type MillisDurationEncoder struct{}
+
+func (e *MillisDurationEncoder) Append(buf *Buffer, enc ObjEncoder, dur time.Duration) {
+	enc.AppendFloat64(buf, float64(dur)/float64(time.Millisecond))
+}
+
+// SecondsDurationEncoder encodes a time.Duration as a floating-point number of seconds.
+type SecondsDurationEncoder struct{}
+
+func (e *SecondsDurationEncoder) Append(buf *Buffer, enc ObjEncoder, dur time.Duration) {
+	enc.AppendFloat64(buf, dur.Seconds())
+}
+
+type CallerEncoder interface {
+	Append(buf *Buffer, enc ObjEncoder, caller EntryCaller)
+}
+
+// ShortCallerEncoder encodes the caller in the form of "package/file:line".
+type ShortCallerEncoder struct{}
+
+func (e *ShortCallerEncoder) Append(buf *Buffer, enc ObjEncoder, caller EntryCaller) {
+	enc.AppendString(buf, caller.TrimmedPath())
+}
+
+// FullCallerEncoder encodes the caller in the form of "/full/path/to/package/file:line".
+type FullCallerEncoder struct{}
+
+func (e *FullCallerEncoder) Append(buf *Buffer, enc ObjEncoder, caller EntryCaller) {
+	enc.AppendString(buf, caller.String())
+}
+
+// FuncCallerEncoder encodes the caller in the form of the fully qualified function name.
+type FuncCallerEncoder struct{}
+
+func (e *FuncCallerEncoder) Append(buf *Buffer, enc ObjEncoder, caller EntryCaller) {
+	enc.AppendString(buf, caller.Function)
+}
+
 type EncoderOpts struct {
 	// colorEnabled is a bool that indicate whether enable the color when encoding a log.
 	colorEnabled bool
@@ -155,10 +275,17 @@ type EncoderOpts struct {
 	timeDisabled bool
 	// lineEnding is the line ending of every log.
 	lineEnding string
+	// levelKey, timeKey and msgKey are the keys of the level, time and message of every log,
+	// the corresponding part is omitted if its key is empty.
+	levelKey string
+	timeKey  string
+	msgKey   string
 
 	timeEncoder     TimeEncoder
 
 	durationEncoder DurationEncoder
+
+	callerEncoder CallerEncoder
 }
 
 type EncoderOpt func(opts *EncoderOpts)
@@ -190,6 +317,30 @@ func DisableTime() EncoderOpt {
 	}
 }
 
+// SetLevelKey sets the key of the level when encoding a log.
+// If the given key is empty, the level is omitted.
+func SetLevelKey(key string) EncoderOpt {
+	return func(opts *EncoderOpts) {
+		opts.levelKey = key
+	}
+}
+
+// SetTimeKey sets the key of the time when encoding a log.
+// If the given key is empty, the time is omitted.
+func SetTimeKey(key string) EncoderOpt {
+	return func(opts *EncoderOpts) {
+		opts.timeKey = key
+	}
+}
+
+// SetMsgKey sets the key of the message when encoding a log.
+// If the given key is empty, the message is omitted.
+func SetMsgKey(key string) EncoderOpt {
+	return func(opts *EncoderOpts) {
+		opts.msgKey = key
+	}
+}
+
 // SetTimeEncoder sets the time encoder to encode a time.Time.
 func SetTimeEncoder(e TimeEncoder) EncoderOpt {
 	return func(opts *EncoderOpts) {
@@ -204,6 +355,13 @@ func SetDurationEncoder(e DurationEncoder) EncoderOpt {
 	}
 }
 
+// SetCallerEncoder sets the caller encoder to encode a EntryCaller.
+func SetCallerEncoder(e CallerEncoder) EncoderOpt {
+	return func(opts *EncoderOpts) {
+		opts.callerEncoder = e
+	}
+}
+
 // SetLineEnding sets the line ending when encoding a log.
 func SetLineEnding(ending string) EncoderOpt {
 	return func(opts *EncoderOpts) {
@@ -294,4 +452,33 @@ func (e *BasicObjEncoder) AppendByteString(buf *Buffer, val []byte) {
 	buf.AppendBytes(val)
 }
 
+// AppendError appends the message of the val.
+func (e *BasicObjEncoder) AppendError(buf *Buffer, val error) {
+	buf.AppendString(val.Error())
+}
 
+// AppendJSON appends the val as a quoted and escaped string, such as "{\"a\":1}".
+func (e *BasicObjEncoder) AppendJSON(buf *Buffer, val []byte) {
+	buf.AppendByte('"')
+	appendEscapedString(buf, bytesToStr(val))
+	buf.AppendByte('"')
+}
+
+// AppendObjectStart appends the beginning of an object in the form of "{k1=v1 k2=v2}".
+func (e *BasicObjEncoder) AppendObjectStart(buf *Buffer) {
+	buf.AppendByte('{')
+}
+
+// AppendObjectKey appends the key of an object in the form of "{k1=v1 k2=v2}".
+func (e *BasicObjEncoder) AppendObjectKey(buf *Buffer, key string, i int) {
+	if i > 0 {
+		buf.AppendByte(' ')
+	}
+	buf.AppendString(key)
+	buf.AppendByte('=')
+}
+
+// AppendObjectEnd appends the end of an object in the form of "{k1=v1 k2=v2}".
+func (e *BasicObjEncoder) AppendObjectEnd(buf *Buffer) {
+	buf.AppendByte('}')
+}
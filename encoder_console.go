@@ -0,0 +1,216 @@
+package wlog
+
+import (
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	// consoleLevelWidth is the width of the level column, it's the length of the longest level string.
+	consoleLevelWidth = 5
+	// consoleIndent is the indent of the lines of errors, multi-line strings and stack traces.
+	consoleIndent = "    "
+
+	consoleKeyColor      = Cyan
+	consoleNumberColor   = Magenta
+	consoleBoolColor     = Yellow
+	consoleDurationColor = Green
+)
+
+// ConsoleEncoder is a human-friendly encoder for the local development, such as:
+//
+//	15:04:05.000 INFO  main.go:10 failed to login  user=root retry=5
+//	    error: connection refused
+//
+// The level column is padded, the field keys and values are colored by type if the color is enabled,
+// and the errors and multi-line strings are encoded on their own indented lines after the message.
+type ConsoleEncoder struct {
+	BasicObjEncoder
+	EncoderOpts
+}
+
+// NewConsoleEncoder returns a ConsoleEncoder, its color is disabled by default,
+// it's recommended to use EnableTerminalColor to enable the color only when writing to a terminal.
+func NewConsoleEncoder(opts ...EncoderOpt) *ConsoleEncoder {
+	e := &ConsoleEncoder{}
+	e.timeEncoder = &ShortTimeEncoder{}
+	e.durationEncoder = &StrDurationEncoder{}
+	e.callerEncoder = &ShortCallerEncoder{}
+	e.lineEnding = defaultLineEnding
+	e.WithOpts(opts...)
+	return e
+}
+
+// EnableTerminalColor enables the color when encoding a log if all the given ws are terminals.
+func EnableTerminalColor(ws ...io.Writer) EncoderOpt {
+	return func(opts *EncoderOpts) {
+		opts.colorEnabled = len(ws) > 0
+		for _, w := range ws {
+			if !isTerminal(w) {
+				opts.colorEnabled = false
+				return
+			}
+		}
+	}
+}
+
+// isTerminal returns true if the w is a terminal.
+func isTerminal(w io.Writer) bool {
+	if iw, ok := w.(*IOWriter); ok {
+		w = iw.w
+	}
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	fi, err := f.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
+
+func (e *ConsoleEncoder) AppendDuration(buf *Buffer, val time.Duration) {
+	e.durationEncoder.Append(buf, e, val)
+}
+
+func (e *ConsoleEncoder) AppendTime(buf *Buffer, val time.Time) {
+	e.timeEncoder.Append(buf, e, val)
+}
+
+func (e *ConsoleEncoder) AppendArray(buf *Buffer, val ArrayEncoder) {
+	buf.AppendByte('[')
+	for i, size := 0, val.Size(); i < size; i++ {
+		if i > 0 {
+			buf.AppendByte(' ')
+		}
+		val.AppendEle(e, buf, i)
+	}
+	buf.AppendByte(']')
+}
+
+func (e *ConsoleEncoder) AppendObject(buf *Buffer, val FieldVal) {
+	val.Encode(e, buf)
+}
+
+func (e *ConsoleEncoder) Encode(buf *Buffer, entry *Entry, fields ...Field) error {
+	// Encode message time.
+	if !e.timeDisabled {
+		e.AppendTime(buf, entry.Time)
+		buf.AppendByte(' ')
+	}
+	// Encode message level with padding.
+	lvlStr := entry.Level.Str(e.levelLower)
+	if e.colorEnabled {
+		buf.AppendString(entry.Level.ColorfulStr(e.levelLower))
+	} else {
+		buf.AppendString(lvlStr)
+	}
+	for i := len(lvlStr); i < consoleLevelWidth; i++ {
+		buf.AppendByte(' ')
+	}
+	// Encode message caller.
+	if entry.Caller.Defined {
+		buf.AppendByte(' ')
+		e.callerEncoder.Append(buf, e, entry.Caller)
+	}
+	// Encode message text.
+	buf.AppendByte(' ')
+	e.AppendString(buf, entry.Msg)
+	// Encode inline fields, the keys of the fields after a namespace are prefixed with it.
+	fields = prefixNamespaces(fields)
+	inline := 0
+	for _, field := range fields {
+		if _, ok := consoleBlockText(field.Val); ok {
+			continue
+		}
+		if inline == 0 {
+			buf.AppendByte(' ')
+		}
+		inline++
+		buf.AppendByte(' ')
+		e.encodeKey(buf, field.Key)
+		buf.AppendByte('=')
+		e.encodeVal(buf, field.Val)
+	}
+	buf.AppendString(e.lineEnding)
+	// Encode errors and multi-line strings on their own lines.
+	for _, field := range fields {
+		text, ok := consoleBlockText(field.Val)
+		if !ok {
+			continue
+		}
+		buf.AppendString(consoleIndent)
+		e.encodeKey(buf, field.Key)
+		buf.AppendByte(':')
+		if strings.IndexByte(text, '\n') == -1 {
+			buf.AppendByte(' ')
+			buf.AppendString(text)
+			buf.AppendString(e.lineEnding)
+			continue
+		}
+		buf.AppendString(e.lineEnding)
+		e.encodeLines(buf, consoleIndent+consoleIndent, text)
+	}
+	// Encode message stack trace.
+	if len(entry.Stack) > 0 {
+		e.encodeLines(buf, consoleIndent, entry.Stack)
+	}
+	return nil
+}
+
+// encodeKey encodes the key of a field to the buf.
+func (e *ConsoleEncoder) encodeKey(buf *Buffer, key string) {
+	if !e.colorEnabled {
+		buf.AppendString(key)
+		return
+	}
+	consoleKeyColor.appendStart(buf)
+	buf.AppendString(key)
+	buf.AppendString(colorReset)
+}
+
+// encodeVal encodes the value of a field to the buf, the value is colored by its type.
+func (e *ConsoleEncoder) encodeVal(buf *Buffer, val FieldVal) {
+	color := NoColor
+	if e.colorEnabled {
+		switch val.(type) {
+		case IntVal, UintVal, FloatVal, ComplexVal:
+			color = consoleNumberColor
+		case BoolVal:
+			color = consoleBoolColor
+		case DurationVal, TimeVal:
+			color = consoleDurationColor
+		}
+	}
+	if color == NoColor {
+		val.Encode(e, buf)
+		return
+	}
+	color.appendStart(buf)
+	val.Encode(e, buf)
+	buf.AppendString(colorReset)
+}
+
+// encodeLines encodes the text to the buf line by line with the indent.
+func (e *ConsoleEncoder) encodeLines(buf *Buffer, indent string, text string) {
+	for _, line := range strings.Split(strings.TrimRight(text, "\n"), "\n") {
+		buf.AppendString(indent)
+		buf.AppendString(line)
+		buf.AppendString(e.lineEnding)
+	}
+}
+
+// consoleBlockText returns the text of the val and true if the val is an error
+// or a multi-line string, which is encoded on its own lines by the ConsoleEncoder.
+func consoleBlockText(val FieldVal) (string, bool) {
+	switch v := val.(type) {
+	case ErrorVal:
+		return v.Err.Error(), true
+	case StringVal:
+		return string(v), strings.IndexByte(string(v), '\n') != -1
+	case ByteStringVal:
+		str := bytesToStr(v)
+		return str, strings.IndexByte(str, '\n') != -1
+	}
+	return "", false
+}
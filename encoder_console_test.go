@@ -0,0 +1,56 @@
+package wlog
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConsoleEncoder(t *testing.T) {
+	buf := GetBuf()
+	defer PutBuf(buf)
+	e := NewConsoleEncoder()
+	entry := &Entry{Level: InfoLvl, Time: time.Date(2018, 1, 2, 15, 4, 5, 6e6, time.Local), Msg: "failed to login"}
+	fields := []Field{
+		String("user", "root"),
+		Err("error", errors.New("connection refused")),
+		Int("retry", 5),
+		String("body", "line1\nline2\n"),
+	}
+	assert.NoError(t, e.Encode(buf, entry, fields...))
+	expected := "15:04:05.006 INFO  failed to login  user=root retry=5\n" +
+		"    error: connection refused\n" +
+		"    body:\n" +
+		"        line1\n" +
+		"        line2\n"
+	assert.Equal(t, expected, buf.String())
+}
+
+func TestConsoleEncoderColor(t *testing.T) {
+	buf := GetBuf()
+	defer PutBuf(buf)
+	e := NewConsoleEncoder(DisableTime(), EnableColor())
+	assert.NoError(t, e.Encode(buf, &Entry{Level: WarnLvl, Msg: "msg"}, Int("retry", 5), Bool("ok", true)))
+	expected := WarnLvl.UpperColorfulStr() + "  msg  " +
+		"\x1b[36mretry\x1b[0m=\x1b[35m5\x1b[0m \x1b[36mok\x1b[0m=\x1b[33mtrue\x1b[0m\n"
+	assert.Equal(t, expected, buf.String())
+}
+
+func TestEnableTerminalColor(t *testing.T) {
+	var opts EncoderOpts
+	EnableTerminalColor(&bytes.Buffer{})(&opts)
+	assert.False(t, opts.colorEnabled)
+	r, w, err := os.Pipe()
+	assert.NoError(t, err)
+	defer r.Close()
+	defer w.Close()
+	opts.colorEnabled = true
+	EnableTerminalColor(NewIOWriter(w))(&opts)
+	assert.False(t, opts.colorEnabled)
+	EnableTerminalColor()(&opts)
+	assert.False(t, opts.colorEnabled)
+}
@@ -1,13 +1,17 @@
 package wlog
 
 import (
+	"math"
 	"time"
+	"unicode/utf8"
 )
 
 const (
-	KeyLevel = "level"
-	KeyTime  = "time"
-	KeyMsg   = "msg"
+	KeyLevel  = "level"
+	KeyTime   = "time"
+	KeyMsg    = "msg"
+	KeyCaller = "caller"
+	KeyStack  = "stacktrace"
 )
 
 type JsonEncoder struct {
@@ -18,22 +22,70 @@ type JsonEncoder struct {
 func NewJsonEncoder(opts ...EncoderOpt) *JsonEncoder {
 	e := &JsonEncoder{}
 	e.durationEncoder = &StrDurationEncoder{}
+	e.callerEncoder = &ShortCallerEncoder{}
 	e.timeEncoder = &FastJsonTimeEncoder{}
 	e.levelLower = true
+	e.levelKey, e.timeKey, e.msgKey = KeyLevel, KeyTime, KeyMsg
 	e.lineEnding = defaultLineEnding
 	e.WithOpts(opts...)
 	return e
 }
 
+// AppendByte appends the val as a JSON string with a single character.
+func (e *JsonEncoder) AppendByte(buf *Buffer, val byte) {
+	buf.AppendByte('"')
+	appendEscapedString(buf, string([]byte{val}))
+	buf.AppendByte('"')
+}
+
+// AppendFloat32 appends the val as a JSON number, or a JSON string if the val is NaN or infinity.
+func (e *JsonEncoder) AppendFloat32(buf *Buffer, val float32) {
+	e.AppendFloat64(buf, float64(val))
+}
+
+// AppendFloat64 appends the val as a JSON number, or a JSON string if the val is NaN or infinity.
+func (e *JsonEncoder) AppendFloat64(buf *Buffer, val float64) {
+	switch {
+	case math.IsNaN(val):
+		buf.AppendString(`"NaN"`)
+	case math.IsInf(val, 1):
+		buf.AppendString(`"+Inf"`)
+	case math.IsInf(val, -1):
+		buf.AppendString(`"-Inf"`)
+	default:
+		buf.AppendFloat64(val)
+	}
+}
+
+// AppendComplex64 appends the val as a JSON string.
+func (e *JsonEncoder) AppendComplex64(buf *Buffer, val complex64) {
+	e.AppendComplex128(buf, complex128(val))
+}
+
+// AppendComplex128 appends the val as a JSON string.
+func (e *JsonEncoder) AppendComplex128(buf *Buffer, val complex128) {
+	buf.AppendByte('"')
+	buf.AppendComplex128(val)
+	buf.AppendByte('"')
+}
+
+// AppendString appends the val as a JSON string, the val is escaped according to RFC 8259
+// and every byte of invalid UTF-8 is replaced with the Unicode replacement character.
 func (e *JsonEncoder) AppendString(buf *Buffer, val string) {
 	buf.AppendByte('"')
-	buf.AppendString(val)
+	appendEscapedString(buf, val)
 	buf.AppendByte('"')
 }
 
-func (e *JsonEncoder) AppendByeString(buf *Buffer, val []byte) {
+// AppendBytes appends the val as a JSON string in the same way as the AppendString.
+func (e *JsonEncoder) AppendBytes(buf *Buffer, val []byte) {
+	e.AppendByteString(buf, val)
+}
+
+// AppendByteString appends the val as a JSON string in the same way as the AppendString.
+func (e *JsonEncoder) AppendByteString(buf *Buffer, val []byte) {
 	buf.AppendByte('"')
-	buf.AppendBytes(val)
+	appendEscapedString(buf, bytesToStr(val))
 	buf.AppendByte('"')
 }
 
@@ -60,35 +112,85 @@ func (e *JsonEncoder) AppendObject(buf *Buffer, val FieldVal) {
 	val.Encode(e, buf)
 }
 
+// AppendError appends the val as a JSON object with its message, type name, causes and stack.
+func (e *JsonEncoder) AppendError(buf *Buffer, val error) {
+	appendObject(e, buf, errorObj{err: val})
+}
+
+// AppendJSON appends the val directly.
+func (e *JsonEncoder) AppendJSON(buf *Buffer, val []byte) {
+	buf.AppendBytes(val)
+}
+
+// AppendObjectStart appends the beginning of a JSON object.
+func (e *JsonEncoder) AppendObjectStart(buf *Buffer) {
+	buf.AppendByte('{')
+}
+
+// AppendObjectKey appends the key of a JSON object.
+func (e *JsonEncoder) AppendObjectKey(buf *Buffer, key string, i int) {
+	if i > 0 {
+		buf.AppendByte(',')
+	}
+	e.encodeKey(buf, key)
+}
+
+// AppendObjectEnd appends the end of a JSON object.
+func (e *JsonEncoder) AppendObjectEnd(buf *Buffer) {
+	buf.AppendByte('}')
+}
+
 func (e *JsonEncoder) Encode(buf *Buffer, entry *Entry, fields ...Field) error {
 	buf.AppendByte('{')
+	start := buf.Len()
 	// Encode message level.
-	e.encodeKey(buf, KeyLevel)
-	if e.colorEnabled {
-		e.AppendString(buf, entry.Level.ColorfulStr(e.levelLower))
-	} else {
-		e.AppendString(buf, entry.Level.Str(e.levelLower))
+	if e.levelKey != "" {
+		e.encodeKey(buf, e.levelKey)
+		if e.colorEnabled {
+			e.AppendString(buf, entry.Level.ColorfulStr(e.levelLower))
+		} else {
+			e.AppendString(buf, entry.Level.Str(e.levelLower))
+		}
 	}
 	// Encode message time.
-	if !e.timeDisabled {
-		buf.AppendByte(',')
-		e.encodeKey(buf, KeyTime)
+	if !e.timeDisabled && e.timeKey != "" {
+		e.encodeSep(buf, start)
+		e.encodeKey(buf, e.timeKey)
 		e.AppendTime(buf, entry.Time)
 	}
+	// Encode message caller.
+	if entry.Caller.Defined {
+		e.encodeSep(buf, start)
+		e.encodeKey(buf, KeyCaller)
+		e.callerEncoder.Append(buf, e, entry.Caller)
+	}
 	// Encode message text.
-	buf.AppendByte(',')
-	e.encodeKey(buf, KeyMsg)
-	e.AppendString(buf, entry.Msg)
-	// Encode message fields.
-	if len(fields) > 0 {
-		buf.AppendByte(',')
-		for i, field := range fields {
-			if i > 0 {
-				buf.AppendByte(',')
-			}
-			e.encodeKey(buf, field.Key)
-			field.Val.Encode(e, buf)
+	if e.msgKey != "" {
+		e.encodeSep(buf, start)
+		e.encodeKey(buf, e.msgKey)
+		e.AppendString(buf, entry.Msg)
+	}
+	// Encode message fields, the fields after a namespace are nested in it.
+	nested := 0
+	for _, field := range fields {
+		e.encodeSep(buf, start)
+		e.encodeKey(buf, field.Key)
+		if _, ok := field.Val.(NamespaceVal); ok {
+			e.AppendObjectStart(buf)
+			start = buf.Len()
+			nested++
+			continue
 		}
+		field.Val.Encode(e, buf)
+	}
+	for ; nested > 0; nested-- {
+		e.AppendObjectEnd(buf)
+	}
+	// Encode message stack trace.
+	if len(entry.Stack) > 0 {
+		e.encodeSep(buf, start)
+		e.encodeKey(buf, KeyStack)
+		e.AppendString(buf, entry.Stack)
 	}
 	buf.AppendByte('}')
 	// Encode the line ending.
@@ -100,3 +202,55 @@ func (e *JsonEncoder) encodeKey(buf *Buffer, key string) {
 	e.AppendString(buf, key)
 	buf.AppendByte(':')
 }
+
+// encodeSep encodes a comma to the buf if anything has been encoded after the start.
+func (e *JsonEncoder) encodeSep(buf *Buffer, start int) {
+	if buf.Len() > start {
+		buf.AppendByte(',')
+	}
+}
+
+// appendEscapedString appends the given s to the buf with the escaped
+// quotation mark, reverse solidus and control characters, every byte of
+// invalid UTF-8 in the s is replaced with the escaped Unicode replacement character.
+func appendEscapedString(buf *Buffer, s string) {
+	start := 0
+	for i := 0; i < len(s); {
+		c := s[i]
+		if c < utf8.RuneSelf {
+			if c >= 0x20 && c != '"' && c != '\\' {
+				i++
+				continue
+			}
+			buf.AppendString(s[start:i])
+			switch c {
+			case '"', '\\':
+				buf.AppendByte('\\')
+				buf.AppendByte(c)
+			case '\n':
+				buf.AppendString("\\n")
+			case '\r':
+				buf.AppendString("\\r")
+			case '\t':
+				buf.AppendString("\\t")
+			default:
+				buf.AppendString("\\u00")
+				buf.AppendByte(lowerDigit[c>>4])
+				buf.AppendByte(lowerDigit[c&0xF])
+			}
+			i++
+			start = i
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size == 1 {
+			buf.AppendString(s[start:i])
+			buf.AppendString("\\ufffd")
+			i++
+			start = i
+			continue
+		}
+		i += size
+	}
+	buf.AppendString(s[start:])
+}
@@ -0,0 +1,113 @@
+package wlog
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+	"unsafe"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJsonEncoderEscape(t *testing.T) {
+	tests := []struct {
+		val      string
+		expected string
+	}{
+		{"plain", `"plain"`},
+		{`a"b\c`, `"a\"b\\c"`},
+		{"line1\nline2\r\ttab", `"line1\nline2\r\ttab"`},
+		{"\x00\x1f\x7f", `"\u0000\u001f` + "\x7f" + `"`},
+		{"中文", `"中文"`},
+		{"a\xffb\xe4\xb8", `"a\ufffdb\ufffd\ufffd"`},
+	}
+	e := NewJsonEncoder()
+	for _, tt := range tests {
+		buf := GetBuf()
+		e.AppendString(buf, tt.val)
+		assert.Equal(t, tt.expected, buf.String())
+		buf.Reset()
+		e.AppendByteString(buf, []byte(tt.val))
+		assert.Equal(t, tt.expected, buf.String())
+		PutBuf(buf)
+	}
+}
+
+func TestJsonEncoderValidJson(t *testing.T) {
+	buf := GetBuf()
+	defer PutBuf(buf)
+	fields := []Field{
+		String("quote\"key", "a\"b\n"),
+		ByteString("bytes", []byte("\x01\xff")),
+		Strings("strings", []string{"\t", "\\"}),
+		Object("byte", ByteVal('"')),
+		Float64("nan", math.NaN()),
+		Float64s("infs", []float64{math.Inf(1), math.Inf(-1)}),
+		Complex128("complex", complex(1, -2)),
+		Ptr("ptr", unsafe.Pointer(buf)),
+	}
+	e := NewJsonEncoder(EnableColor())
+	assert.NoError(t, e.Encode(buf, &Entry{Level: InfoLvl, Msg: "msg\"\\"}, fields...))
+	var m map[string]interface{}
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &m), buf.String())
+	assert.Equal(t, "msg\"\\", m[KeyMsg])
+	assert.Equal(t, "a\"b\n", m["quote\"key"])
+	assert.Equal(t, "\x01�", m["bytes"])
+	assert.Equal(t, "NaN", m["nan"])
+	assert.Equal(t, "(1-2i)", m["complex"])
+}
+
+func TestJsonEncoderEscapeAllocs(t *testing.T) {
+	e := NewJsonEncoder()
+	buf := GetBuf()
+	defer PutBuf(buf)
+	bs := []byte("a\"b\\c\n\x00\xff中文")
+	allocs := testing.AllocsPerRun(100, func() {
+		buf.Reset()
+		e.AppendString(buf, "a\"b\\c\n\x00\xff中文")
+		e.AppendByteString(buf, bs)
+	})
+	assert.Zero(t, allocs)
+}
+
+func FuzzJsonEncoder(f *testing.F) {
+	f.Add("key", "msg", "val")
+	f.Add("k\"ey", "m\nsg", "v\\al")
+	f.Add("\x00", "\xff\xfe", " 中文")
+	e := NewJsonEncoder()
+	f.Fuzz(func(t *testing.T, key, msg, val string) {
+		buf := GetBuf()
+		defer PutBuf(buf)
+		err := e.Encode(buf, &Entry{Level: InfoLvl, Msg: msg},
+			String(key, val), ByteStrings(KeyLevel+"s", [][]byte{[]byte(val)}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var m map[string]interface{}
+		if err := json.Unmarshal(buf.Bytes(), &m); err != nil {
+			t.Fatalf("invalid JSON %q: %v", buf.String(), err)
+		}
+		if m[KeyMsg] != jsonRoundTrip(t, msg) {
+			t.Fatalf("msg: got %q, want %q", m[KeyMsg], jsonRoundTrip(t, msg))
+		}
+		roundTripKey := jsonRoundTrip(t, key)
+		if roundTripKey != KeyLevel && roundTripKey != KeyTime && roundTripKey != KeyMsg && roundTripKey != KeyLevel+"s" {
+			if m[roundTripKey] != jsonRoundTrip(t, val) {
+				t.Fatalf("val: got %q, want %q", m[roundTripKey], jsonRoundTrip(t, val))
+			}
+		}
+	})
+}
+
+// jsonRoundTrip returns the s after it is marshaled and unmarshaled by the encoding/json.
+func jsonRoundTrip(t *testing.T, s string) string {
+	bs, err := json.Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out string
+	if err := json.Unmarshal(bs, &out); err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
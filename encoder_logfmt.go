@@ -0,0 +1,187 @@
+package wlog
+
+import (
+	"time"
+	"unicode/utf8"
+)
+
+// LogfmtEncoder encodes a log message into a line of logfmt, such as:
+//
+//	level=info time="2006-01-02 15:04:05" msg="failed to login" user=root
+//
+// A value is quoted and escaped only if it's empty or contains any spaces,
+// equal signs, quotation marks, control characters or invalid UTF-8.
+// An array is encoded in the form of "[a b c]", and its elements are quoted in the same way,
+// so that an element with spaces is distinguished, such as tags="[a \"b c\"]".
+type LogfmtEncoder struct {
+	BasicObjEncoder
+	EncoderOpts
+}
+
+func NewLogfmtEncoder(opts ...EncoderOpt) *LogfmtEncoder {
+	e := &LogfmtEncoder{}
+	e.timeEncoder = &FastTextTimeEncoder{}
+	e.durationEncoder = &StrDurationEncoder{}
+	e.callerEncoder = &ShortCallerEncoder{}
+	e.levelLower = true
+	e.levelKey, e.timeKey, e.msgKey = KeyLevel, KeyTime, KeyMsg
+	e.lineEnding = defaultLineEnding
+	e.WithOpts(opts...)
+	return e
+}
+
+func (e *LogfmtEncoder) AppendDuration(buf *Buffer, val time.Duration) {
+	e.durationEncoder.Append(buf, e, val)
+}
+
+func (e *LogfmtEncoder) AppendTime(buf *Buffer, val time.Time) {
+	e.timeEncoder.Append(buf, e, val)
+}
+
+func (e *LogfmtEncoder) AppendArray(buf *Buffer, val ArrayEncoder) {
+	buf.AppendByte('[')
+	ele := GetBuf()
+	for i, size := 0, val.Size(); i < size; i++ {
+		if i > 0 {
+			buf.AppendByte(' ')
+		}
+		ele.Reset()
+		val.AppendEle(e, ele, i)
+		appendLogfmtValue(buf, ele.Bytes())
+	}
+	PutBuf(ele)
+	buf.AppendByte(']')
+}
+
+// AppendJSON appends the val as a string, it's quoted and escaped once as a value, such as raw="{\"a\":1}".
+func (e *LogfmtEncoder) AppendJSON(buf *Buffer, val []byte) {
+	e.AppendByteString(buf, val)
+}
+
+func (e *LogfmtEncoder) AppendObject(buf *Buffer, val FieldVal) {
+	val.Encode(e, buf)
+}
+
+func (e *LogfmtEncoder) Encode(buf *Buffer, entry *Entry, fields ...Field) error {
+	start := buf.Len()
+	val := GetBuf()
+	// Encode message level.
+	if e.levelKey != "" {
+		e.encodeKey(buf, e.levelKey)
+		if e.colorEnabled {
+			e.AppendString(val, entry.Level.ColorfulStr(e.levelLower))
+		} else {
+			e.AppendString(val, entry.Level.Str(e.levelLower))
+		}
+		e.encodeVal(buf, val)
+	}
+	// Encode message time.
+	if !e.timeDisabled && e.timeKey != "" {
+		e.encodeSep(buf, start)
+		e.encodeKey(buf, e.timeKey)
+		e.AppendTime(val, entry.Time)
+		e.encodeVal(buf, val)
+	}
+	// Encode message caller.
+	if entry.Caller.Defined {
+		e.encodeSep(buf, start)
+		e.encodeKey(buf, KeyCaller)
+		e.callerEncoder.Append(val, e, entry.Caller)
+		e.encodeVal(buf, val)
+	}
+	// Encode message text.
+	if e.msgKey != "" {
+		e.encodeSep(buf, start)
+		e.encodeKey(buf, e.msgKey)
+		e.AppendString(val, entry.Msg)
+		e.encodeVal(buf, val)
+	}
+	// Encode message fields, the keys of the fields after a namespace are prefixed with it.
+	for _, field := range prefixNamespaces(fields) {
+		e.encodeSep(buf, start)
+		e.encodeKey(buf, field.Key)
+		field.Val.Encode(e, val)
+		e.encodeVal(buf, val)
+	}
+	// Encode message stack trace.
+	if len(entry.Stack) > 0 {
+		e.encodeSep(buf, start)
+		e.encodeKey(buf, KeyStack)
+		e.AppendString(val, entry.Stack)
+		e.encodeVal(buf, val)
+	}
+	PutBuf(val)
+	// Encode the line ending.
+	buf.AppendString(e.lineEnding)
+	return nil
+}
+
+// encodeSep encodes a space to the buf if anything has been encoded after the start.
+func (e *LogfmtEncoder) encodeSep(buf *Buffer, start int) {
+	if buf.Len() > start {
+		buf.AppendByte(' ')
+	}
+}
+
+// encodeKey encodes the key and the equal sign to the buf,
+// every byte that is not allowed in a key is replaced with '_'.
+func (e *LogfmtEncoder) encodeKey(buf *Buffer, key string) {
+	if len(key) == 0 {
+		buf.AppendByte('_')
+	}
+	for i := 0; i < len(key); i++ {
+		c := key[i]
+		if isLogfmtSpecial(c) {
+			buf.AppendByte('_')
+		} else {
+			buf.AppendByte(c)
+		}
+	}
+	buf.AppendByte('=')
+}
+
+// encodeVal encodes the encoded value in the val to the buf, and then resets the val.
+func (e *LogfmtEncoder) encodeVal(buf *Buffer, val *Buffer) {
+	appendLogfmtValue(buf, val.Bytes())
+	val.Reset()
+}
+
+// appendLogfmtValue appends the val to the buf, the val is quoted and escaped if necessary.
+func appendLogfmtValue(buf *Buffer, val []byte) {
+	if !needsLogfmtQuote(val) {
+		buf.AppendBytes(val)
+		return
+	}
+	buf.AppendByte('"')
+	appendEscapedString(buf, bytesToStr(val))
+	buf.AppendByte('"')
+}
+
+// needsLogfmtQuote returns true if the val is empty or contains any spaces, equal signs,
+// quotation marks, control characters or invalid UTF-8.
+func needsLogfmtQuote(val []byte) bool {
+	if len(val) == 0 {
+		return true
+	}
+	for i := 0; i < len(val); {
+		c := val[i]
+		if c < utf8.RuneSelf {
+			if isLogfmtSpecial(c) {
+				return true
+			}
+			i++
+			continue
+		}
+		r, size := utf8.DecodeRune(val[i:])
+		if r == utf8.RuneError && size == 1 {
+			return true
+		}
+		i += size
+	}
+	return false
+}
+
+// isLogfmtSpecial returns true if the c is a space, equal sign, quotation mark or control character.
+func isLogfmtSpecial(c byte) bool {
+	return c <= ' ' || c == '=' || c == '"' || c == 0x7f
+}
@@ -0,0 +1,37 @@
+package wlog
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogfmtEncoder(t *testing.T) {
+	buf := GetBuf()
+	defer PutBuf(buf)
+	e := NewLogfmtEncoder(DisableTime())
+	fields := []Field{
+		String("user", "root"),
+		String("empty", ""),
+		String("quoted", `say "hi"`),
+		String("multi line", "a\nb"),
+		Int("retry", 5),
+		Strings("tags", []string{"a", "b c"}),
+		ByteString("invalid", []byte("\xff")),
+	}
+	assert.NoError(t, e.Encode(buf, &Entry{Level: InfoLvl, Msg: "failed to login"}, fields...))
+	expected := `level=info msg="failed to login" user=root empty="" quoted="say \"hi\"" ` +
+		`multi_line="a\nb" retry=5 tags="[a \"b c\"]" invalid="\ufffd"` + "\n"
+	assert.Equal(t, expected, buf.String())
+
+	// An element with spaces is distinguished from multiple elements.
+	le := NewLogfmtEncoder(SetLevelKey(""), DisableTime(), SetMsgKey(""))
+	assert.Equal(t, `tags="[\"a b\" c]"`+"\n", encodeFields(le, Strings("tags", []string{"a b", "c"})))
+	assert.Equal(t, `tags="[a b c]"`+"\n", encodeFields(le, Strings("tags", []string{"a", "b", "c"})))
+}
+
+func TestConfigLogfmtEncoder(t *testing.T) {
+	e, err := Config{Encoder: "logfmt"}.BuildEncoder()
+	assert.NoError(t, err)
+	assert.IsType(t, &LogfmtEncoder{}, e)
+}
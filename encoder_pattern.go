@@ -0,0 +1,292 @@
+package wlog
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+	"unicode/utf8"
+)
+
+// defaultPattern is the pattern of a PatternEncoder created with an empty pattern.
+const defaultPattern = "%d %p %m %fields%n"
+
+// patternAppender appends a part of the log message to the buf.
+type patternAppender func(e *PatternEncoder, buf *Buffer, entry *Entry, fields []Field)
+
+// PatternEncoder encodes a log message in the layout described by a log4j-style pattern,
+// such as "%d{15:04:05.000} %-5p [%logger] %m %fields%n".
+//
+// The pattern is compiled once when creating the PatternEncoder, and the supported verbs are as follow:
+//
+//	%d, %date        the time encoded by the TimeEncoder, or %d{layout} with a Go time layout
+//	%p, %level       the level, its case and color are determined by the EncoderOpts
+//	%m, %msg         the message
+//	%c, %caller      the caller encoded by the CallerEncoder
+//	%X{key}          the value of the field with the key
+//	%logger          the value of the field with the key "logger", the same as %X{logger}
+//	%fields          all fields except the fields referenced by %X and %logger
+//	%stack           the stack trace
+//	%n               the line ending
+//	%%               a percent sign
+//
+// A verb can be padded to a minimum width, such as "%5p" is right-aligned and "%-5p" is left-aligned.
+// Only %d and %X take an argument in braces, an argument of any other verb is invalid.
+type PatternEncoder struct {
+	BasicObjEncoder
+	EncoderOpts
+	pattern   string
+	appenders []patternAppender
+	// refKeys are the keys of the fields referenced by %X and %logger.
+	refKeys []string
+}
+
+// NewPatternEncoder returns a PatternEncoder with the given pattern and opts,
+// it returns an error if the pattern is invalid.
+// If the given pattern is empty, the default pattern "%d %p %m %fields%n" is used.
+func NewPatternEncoder(pattern string, opts ...EncoderOpt) (*PatternEncoder, error) {
+	if pattern == "" {
+		pattern = defaultPattern
+	}
+	e := &PatternEncoder{pattern: pattern}
+	e.timeEncoder = &FastTextTimeEncoder{}
+	e.durationEncoder = &StrDurationEncoder{}
+	e.callerEncoder = &ShortCallerEncoder{}
+	e.lineEnding = defaultLineEnding
+	e.WithOpts(opts...)
+	if err := e.compile(); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+func (e *PatternEncoder) AppendDuration(buf *Buffer, val time.Duration) {
+	e.durationEncoder.Append(buf, e, val)
+}
+
+func (e *PatternEncoder) AppendTime(buf *Buffer, val time.Time) {
+	e.timeEncoder.Append(buf, e, val)
+}
+
+func (e *PatternEncoder) AppendArray(buf *Buffer, val ArrayEncoder) {
+	buf.AppendByte('[')
+	for i, size := 0, val.Size(); i < size; i++ {
+		if i > 0 {
+			buf.AppendByte(' ')
+		}
+		val.AppendEle(e, buf, i)
+	}
+	buf.AppendByte(']')
+}
+
+func (e *PatternEncoder) AppendObject(buf *Buffer, val FieldVal) {
+	val.Encode(e, buf)
+}
+
+func (e *PatternEncoder) Encode(buf *Buffer, entry *Entry, fields ...Field) error {
+	// The keys of the fields after a namespace are prefixed with it, such as %X{http.status}.
+	fields = prefixNamespaces(fields)
+	for _, appender := range e.appenders {
+		appender(e, buf, entry, fields)
+	}
+	return nil
+}
+
+// compile compiles the pattern into a sequence of appenders.
+func (e *PatternEncoder) compile() error {
+	p := e.pattern
+	for len(p) > 0 {
+		i := 0
+		for i < len(p) && p[i] != '%' {
+			i++
+		}
+		if i > 0 {
+			e.appenders = append(e.appenders, literalAppender(p[:i]))
+			p = p[i:]
+			continue
+		}
+		// Parse the alignment and width.
+		i = 1
+		left := false
+		if i < len(p) && p[i] == '-' {
+			left = true
+			i++
+		}
+		start := i
+		for i < len(p) && p[i] >= '0' && p[i] <= '9' {
+			i++
+		}
+		width := 0
+		if i > start {
+			width, _ = strconv.Atoi(p[start:i])
+		}
+		// Parse the verb name, it's either a percent sign or a sequence of letters.
+		start = i
+		if i < len(p) && p[i] == '%' {
+			i++
+		} else {
+			for i < len(p) && (p[i] >= 'a' && p[i] <= 'z' || p[i] >= 'A' && p[i] <= 'Z') {
+				i++
+			}
+		}
+		name := p[start:i]
+		// Parse the argument in braces.
+		arg, hasArg := "", false
+		if i < len(p) && p[i] == '{' {
+			end := i + 1
+			for end < len(p) && p[end] != '}' {
+				end++
+			}
+			if end == len(p) {
+				return fmt.Errorf("PatternEncoder: unclosed brace of %q in pattern %q", p[:i], e.pattern)
+			}
+			arg, hasArg = p[i+1:end], true
+			i = end + 1
+		}
+		appender, err := e.verbAppender(name, arg, hasArg, width, left)
+		if err != nil {
+			return err
+		}
+		e.appenders = append(e.appenders, appender)
+		p = p[i:]
+	}
+	return nil
+}
+
+// verbAppender returns the appender of the verb with the given name and argument.
+func (e *PatternEncoder) verbAppender(name, arg string, hasArg bool, width int, left bool) (patternAppender, error) {
+	if hasArg && name != "d" && name != "date" && name != "X" {
+		return nil, fmt.Errorf("PatternEncoder: %%%v does not take an argument in pattern %q", name, e.pattern)
+	}
+	var appender patternAppender
+	switch name {
+	case "%":
+		appender = literalAppender("%")
+	case "d", "date":
+		if hasArg {
+			te := &LayoutTimeEncoder{Layout: arg}
+			appender = func(e *PatternEncoder, buf *Buffer, entry *Entry, fields []Field) {
+				te.Append(buf, e, entry.Time)
+			}
+		} else {
+			appender = func(e *PatternEncoder, buf *Buffer, entry *Entry, fields []Field) {
+				e.AppendTime(buf, entry.Time)
+			}
+		}
+	case "p", "level":
+		// The level pads itself to exclude the color from the width.
+		return levelAppender(width, left), nil
+	case "m", "msg":
+		appender = func(e *PatternEncoder, buf *Buffer, entry *Entry, fields []Field) {
+			e.AppendString(buf, entry.Msg)
+		}
+	case "c", "caller":
+		appender = func(e *PatternEncoder, buf *Buffer, entry *Entry, fields []Field) {
+			if entry.Caller.Defined {
+				e.callerEncoder.Append(buf, e, entry.Caller)
+			}
+		}
+	case "logger":
+		appender = e.fieldAppender("logger")
+	case "X":
+		if !hasArg || arg == "" {
+			return nil, fmt.Errorf("PatternEncoder: %%X requires a key in braces in pattern %q", e.pattern)
+		}
+		appender = e.fieldAppender(arg)
+	case "fields":
+		appender = func(e *PatternEncoder, buf *Buffer, entry *Entry, fields []Field) {
+			e.encodeFields(buf, fields)
+		}
+	case "stack":
+		appender = func(e *PatternEncoder, buf *Buffer, entry *Entry, fields []Field) {
+			buf.AppendString(entry.Stack)
+		}
+	case "n":
+		appender = func(e *PatternEncoder, buf *Buffer, entry *Entry, fields []Field) {
+			buf.AppendString(e.lineEnding)
+		}
+	default:
+		return nil, fmt.Errorf("PatternEncoder: unknown verb %%%v in pattern %q", name, e.pattern)
+	}
+	if width == 0 {
+		return appender, nil
+	}
+	return padAppender(appender, width, left), nil
+}
+
+// fieldAppender returns an appender that appends the value of the field with the given key.
+func (e *PatternEncoder) fieldAppender(key string) patternAppender {
+	e.refKeys = append(e.refKeys, key)
+	return func(e *PatternEncoder, buf *Buffer, entry *Entry, fields []Field) {
+		// The last field wins if multiple fields have the same key.
+		for i := len(fields) - 1; i >= 0; i-- {
+			if fields[i].Key == key {
+				fields[i].Val.Encode(e, buf)
+				return
+			}
+		}
+	}
+}
+
+// encodeFields encodes all fields except the fields referenced by %X and %logger.
+func (e *PatternEncoder) encodeFields(buf *Buffer, fields []Field) {
+	n := 0
+	for _, field := range fields {
+		if containsStr(e.refKeys, field.Key) {
+			continue
+		}
+		if n > 0 {
+			buf.AppendByte(' ')
+		}
+		n++
+		e.AppendString(buf, field.Key)
+		buf.AppendByte('=')
+		field.Val.Encode(e, buf)
+	}
+}
+
+func literalAppender(literal string) patternAppender {
+	return func(e *PatternEncoder, buf *Buffer, entry *Entry, fields []Field) {
+		buf.AppendString(literal)
+	}
+}
+
+func levelAppender(width int, left bool) patternAppender {
+	return func(e *PatternEncoder, buf *Buffer, entry *Entry, fields []Field) {
+		str := entry.Level.Str(e.levelLower)
+		if !left {
+			appendPadding(buf, width-len(str))
+		}
+		if e.colorEnabled {
+			buf.AppendString(entry.Level.ColorfulStr(e.levelLower))
+		} else {
+			buf.AppendString(str)
+		}
+		if left {
+			appendPadding(buf, width-len(str))
+		}
+	}
+}
+
+// padAppender returns an appender that pads the output of the given appender to the width.
+func padAppender(appender patternAppender, width int, left bool) patternAppender {
+	return func(e *PatternEncoder, buf *Buffer, entry *Entry, fields []Field) {
+		if left {
+			start := buf.Len()
+			appender(e, buf, entry, fields)
+			appendPadding(buf, width-utf8.RuneCount(buf.Bytes()[start:]))
+			return
+		}
+		tmp := GetBuf()
+		appender(e, tmp, entry, fields)
+		appendPadding(buf, width-utf8.RuneCount(tmp.Bytes()))
+		buf.AppendBytes(tmp.Bytes())
+		PutBuf(tmp)
+	}
+}
+
+// appendPadding appends n spaces to the buf.
+func appendPadding(buf *Buffer, n int) {
+	for ; n > 0; n-- {
+		buf.AppendByte(' ')
+	}
+}
@@ -0,0 +1,42 @@
+package wlog
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPatternEncoder(t *testing.T) {
+	entry := &Entry{Level: InfoLvl, Time: time.Date(2018, 1, 2, 15, 4, 5, 6e6, time.Local), Msg: "failed to login"}
+	fields := []Field{String("logger", "user"), String("user", "root"), Int("retry", 5)}
+	tests := []struct {
+		pattern  string
+		opts     []EncoderOpt
+		expected string
+	}{
+		{"%d{15:04:05.000} %-5p [%logger] %m %fields%n", nil,
+			"15:04:05.006 INFO  [user] failed to login user=root retry=5\n"},
+		{"%d %5level %msg%n", []EncoderOpt{SetLevelLower()}, "2018-01-02 15:04:05  info failed to login\n"},
+		{"%p|%X{user}|%-6X{retry}|100%%", nil, "INFO|root|5     |100%"},
+		{"%-6p|", []EncoderOpt{EnableColor()}, InfoLvl.UpperColorfulStr() + "  |"},
+		{"", nil, "2018-01-02 15:04:05 INFO failed to login logger=user user=root retry=5\n"},
+	}
+	for _, tt := range tests {
+		e, err := NewPatternEncoder(tt.pattern, tt.opts...)
+		assert.NoError(t, err)
+		buf := GetBuf()
+		assert.NoError(t, e.Encode(buf, entry, fields...))
+		assert.Equal(t, tt.expected, buf.String(), tt.pattern)
+		PutBuf(buf)
+	}
+}
+
+func TestPatternEncoderInvalid(t *testing.T) {
+	for _, pattern := range []string{"%q", "%d{15:04", "%X", "%X{}", "%m{x}", "%-5p{x}", "%%{x}"} {
+		_, err := NewPatternEncoder(pattern)
+		assert.Error(t, err, pattern)
+	}
+	_, err := Config{Encoder: "pattern", EncoderConfig: EncoderConfig{Pattern: "%q"}}.Create()
+	assert.Error(t, err)
+}
@@ -0,0 +1,120 @@
+package wlog
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimeEncoders(t *testing.T) {
+	tm := time.Date(2018, 1, 2, 15, 4, 5, 123456789, time.FixedZone("CST", 8*3600))
+	tests := []struct {
+		te   TimeEncoder
+		text string
+		json string
+	}{
+		{LayoutTimeEncoder{Layout: "15:04:05"}, "15:04:05", `"15:04:05"`},
+		{&ShortTimeEncoder{}, "15:04:05.123", `"15:04:05.123"`},
+		{&RFC3339TimeEncoder{}, "2018-01-02T15:04:05+08:00", `"2018-01-02T15:04:05+08:00"`},
+		{&RFC3339NanoTimeEncoder{}, "2018-01-02T15:04:05.123456789+08:00", `"2018-01-02T15:04:05.123456789+08:00"`},
+		{&EpochTimeEncoder{}, "1514876645", "1514876645"},
+		{&EpochTimeEncoder{Unit: time.Millisecond}, "1514876645123", "1514876645123"},
+		{&EpochTimeEncoder{Unit: time.Microsecond}, "1514876645123456", "1514876645123456"},
+		{&EpochTimeEncoder{Unit: time.Nanosecond}, "1514876645123456789", "1514876645123456789"},
+		{&EpochTimeEncoder{Unit: time.Minute}, "25247944", "25247944"},
+		{&EpochTimeEncoder{Unit: time.Hour}, "420799", "420799"},
+		{&EpochTimeEncoder{Unit: 3 * time.Millisecond}, "504958881707", "504958881707"},
+		{&UTCTimeEncoder{Inner: &RFC3339TimeEncoder{}}, "2018-01-02T07:04:05Z", `"2018-01-02T07:04:05Z"`},
+	}
+	for _, tt := range tests {
+		buf := GetBuf()
+		tt.te.Append(buf, NewTextEncoder(), tm)
+		assert.Equal(t, tt.text, buf.String())
+		buf.Reset()
+		tt.te.Append(buf, NewJsonEncoder(), tm)
+		assert.Equal(t, tt.json, buf.String())
+		PutBuf(buf)
+	}
+}
+
+func TestConfigTimeEncoder(t *testing.T) {
+	for _, name := range []string{"rfc3339", "rfc3339nano", "epoch", "epoch_millis", "epoch_micros", "epoch_nanos"} {
+		_, err := Config{EncoderConfig: EncoderConfig{TimeEncoder: name, TimeUTC: true}}.BuildEncoder()
+		assert.NoError(t, err, name)
+	}
+	_, err := Config{EncoderConfig: EncoderConfig{TimeEncoder: "layout", TimeLayout: "15:04"}}.BuildEncoder()
+	assert.NoError(t, err)
+	_, err = Config{EncoderConfig: EncoderConfig{TimeEncoder: "layout"}}.BuildEncoder()
+	assert.Error(t, err)
+	_, err = Config{EncoderConfig: EncoderConfig{TimeEncoder: "unknown"}}.BuildEncoder()
+	assert.Error(t, err)
+}
+
+func TestConfigCallerEncoder(t *testing.T) {
+	for _, name := range []string{"", "short", "full", "func"} {
+		_, err := Config{EncoderConfig: EncoderConfig{CallerEncoder: name}}.BuildEncoder()
+		assert.NoError(t, err, name)
+	}
+	_, err := Config{EncoderConfig: EncoderConfig{CallerEncoder: "fulll"}}.BuildEncoder()
+	assert.EqualError(t, err, `Config: unknown caller encoder "fulll"`)
+	assert.PanicsWithError(t, `Config: unknown caller encoder "fulll"`, func() {
+		Config{EncoderConfig: EncoderConfig{CallerEncoder: "fulll"}}.CreateEncoder()
+	})
+	assert.IsType(t, &TextEncoder{}, Config{}.CreateEncoder())
+}
+
+func TestDurationEncoders(t *testing.T) {
+	dur := 1500 * time.Millisecond
+	tests := []struct {
+		opt      EncoderOpt
+		expected string
+	}{
+		{SetDurationEncoder(&StrDurationEncoder{}), `{"dur":"1.5s","durs":["1.5s"]}`},
+		{SetDurationEncoder(&NanosDurationEncoder{}), `{"dur":1500000000,"durs":[1500000000]}`},
+		{SetDurationEncoder(&MillisDurationEncoder{}), `{"dur":1500,"durs":[1500]}`},
+		{SetDurationEncoder(&SecondsDurationEncoder{}), `{"dur":1.5,"durs":[1.5]}`},
+	}
+	for _, tt := range tests {
+		buf := GetBuf()
+		e := NewJsonEncoder(SetLevelKey(""), DisableTime(), SetMsgKey(""), tt.opt)
+		assert.NoError(t, e.Encode(buf, &Entry{}, Duration("dur", dur), Durations("durs", []time.Duration{dur})))
+		assert.Equal(t, tt.expected+"\n", buf.String())
+		PutBuf(buf)
+	}
+	for _, name := range []string{"", "string", "nanos", "millis", "seconds"} {
+		_, err := Config{EncoderConfig: EncoderConfig{DurationEncoder: name}}.BuildEncoder()
+		assert.NoError(t, err, name)
+	}
+	_, err := Config{EncoderConfig: EncoderConfig{DurationEncoder: "unknown"}}.BuildEncoder()
+	assert.Error(t, err)
+}
+
+func TestEncoderKeys(t *testing.T) {
+	entry := &Entry{Level: InfoLvl, Time: time.Date(2018, 1, 2, 15, 4, 5, 0, time.Local), Msg: "msg"}
+	opts := []EncoderOpt{SetLevelKey("severity"), SetTimeKey("@timestamp"), SetMsgKey("message")}
+	omitOpts := []EncoderOpt{SetLevelKey(""), SetTimeKey(""), SetMsgKey("")}
+	tests := []struct {
+		encoder  Encoder
+		expected string
+	}{
+		{NewJsonEncoder(opts...), `{"severity":"info","@timestamp":"2018-01-02 15:04:05","message":"msg","k":1}`},
+		{NewJsonEncoder(omitOpts...), `{"k":1}`},
+		{NewLogfmtEncoder(opts...), `severity=info @timestamp="2018-01-02 15:04:05" message=msg k=1`},
+		{NewLogfmtEncoder(omitOpts...), `k=1`},
+		{NewTextEncoder(opts...), `[INFO]  2018-01-02 15:04:05  msg  [k=1]`},
+		{NewTextEncoder(omitOpts...), `[k=1]`},
+	}
+	for _, tt := range tests {
+		buf := GetBuf()
+		assert.NoError(t, tt.encoder.Encode(buf, entry, Int("k", 1)))
+		assert.Equal(t, tt.expected+"\n", buf.String())
+		PutBuf(buf)
+	}
+	e, err := Config{Encoder: "json", EncoderConfig: EncoderConfig{LevelKey: "severity", TimeKey: "-"}}.BuildEncoder()
+	assert.NoError(t, err)
+	buf := GetBuf()
+	defer PutBuf(buf)
+	assert.NoError(t, e.Encode(buf, entry))
+	assert.Equal(t, `{"severity":"info","msg":"msg"}`+"\n", buf.String())
+}
@@ -1,6 +1,13 @@
 package wlog
 
-import "time"
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// textIndent is the indent of the lines of errors and stack traces.
+const textIndent = "    "
 
 type TextEncoder struct {
 	BasicObjEncoder
@@ -11,6 +18,8 @@ func NewTextEncoder(opts ...EncoderOpt) *TextEncoder {
 	e := &TextEncoder{}
 	e.timeEncoder = &FastTextTimeEncoder{}
 	e.durationEncoder = &StrDurationEncoder{}
+	e.callerEncoder = &ShortCallerEncoder{}
+	e.levelKey, e.timeKey, e.msgKey = KeyLevel, KeyTime, KeyMsg
 	e.lineEnding = defaultLineEnding
 	e.WithOpts(opts...)
 	return e
@@ -39,49 +48,199 @@ func (e *TextEncoder) AppendObject(buf *Buffer, val FieldVal) {
 	val.Encode(e, buf)
 }
 
+// AppendError appends the message of the val with the line breaks escaped,
+// so that an error never breaks the line of the fields.
+func (e *TextEncoder) AppendError(buf *Buffer, val error) {
+	msg := val.Error()
+	for i := 0; i < len(msg); i++ {
+		switch c := msg[i]; c {
+		case '\n':
+			buf.AppendString(`\n`)
+		case '\r':
+			buf.AppendString(`\r`)
+		default:
+			buf.AppendByte(c)
+		}
+	}
+}
+
 func (e *TextEncoder) Encode(buf *Buffer, entry *Entry, fields ...Field) error {
+	start := buf.Len()
 	// Encode message level.
-	buf.AppendByte('[')
-	if e.colorEnabled {
-		e.AppendString(buf, entry.Level.ColorfulStr(e.levelLower))
-	} else {
-		e.AppendString(buf, entry.Level.Str(e.levelLower))
+	if e.levelKey != "" {
+		buf.AppendByte('[')
+		if e.colorEnabled {
+			e.AppendString(buf, entry.Level.ColorfulStr(e.levelLower))
+		} else {
+			e.AppendString(buf, entry.Level.Str(e.levelLower))
+		}
+		buf.AppendByte(']')
 	}
-	buf.AppendByte(']')
 	// Encode message time.
-	if !e.timeDisabled {
-		e.encodeSep(buf)
+	if !e.timeDisabled && e.timeKey != "" {
+		e.encodeSep(buf, start)
 		e.AppendTime(buf, entry.Time)
-		//e.AppendString(buf, entry.Time.Format(e.timeLayout))
+	}
+	// Encode message caller.
+	if entry.Caller.Defined {
+		e.encodeSep(buf, start)
+		e.callerEncoder.Append(buf, e, entry.Caller)
 	}
 	// Encode message text.
-	if len(entry.Msg) > 0 {
-		e.encodeSep(buf)
+	if len(entry.Msg) > 0 && e.msgKey != "" {
+		e.encodeSep(buf, start)
 		e.AppendString(buf, entry.Msg)
 	}
+	// Encode message fields, the keys of the fields after a namespace are prefixed with it.
+	fields = prefixNamespaces(fields)
+	var fe *textFieldEncoder
 	n := len(fields)
-	// Encode message fields.
 	if n > 0 {
-		e.encodeSep(buf)
+		e.encodeSep(buf, start)
 		buf.AppendByte('[')
+		fe = &textFieldEncoder{TextEncoder: e}
 		for i, field := range fields {
 			if i > 0 {
 				buf.AppendByte(' ')
 			}
 			e.AppendString(buf, field.Key)
 			buf.AppendByte('=')
-			field.Val.Encode(e, buf)
+			fe.path = append(fe.path[:0], field.Key)
+			field.Val.Encode(fe, buf)
 		}
 		buf.AppendByte(']')
 	}
 	// Encode the line ending.
 	buf.AppendString(e.lineEnding)
+	// Encode the errors of the fields with their type names, causes and stacks line by line,
+	// including the errors nested in the arrays and objects.
+	if fe != nil {
+		for _, fe := range fe.errs {
+			e.encodeError(buf, textIndent, fe.label, fe.err, false)
+		}
+	}
+	// Encode message stack trace, every line of it is indented.
+	if len(entry.Stack) > 0 {
+		e.encodeLines(buf, textIndent, entry.Stack)
+	}
 	return nil
 }
 
-// encodeSep encodes a specified separator to the buf between two independent fields.
+// encodeSep encodes a specified separator to the buf between two independent fields
+// if anything has been encoded after the start.
 // The independent fields are as follows: "Level", "Time", "Msg" and "Field".
-func (e *TextEncoder) encodeSep(buf *Buffer) {
+func (e *TextEncoder) encodeSep(buf *Buffer, start int) {
+	if buf.Len() == start {
+		return
+	}
 	buf.AppendByte(' ')
 	buf.AppendByte(' ')
 }
+
+// encodeError encodes the err to the buf in the form of "label (type): message" with the indent,
+// and then encodes its causes as "caused by" lines and its stack with a deeper indent, such as:
+//
+//	err (*fmt.wrapError): query failed: timeout
+//	    caused by (*errors.errorString): timeout
+func (e *TextEncoder) encodeError(buf *Buffer, indent, label string, err error, chained bool) {
+	buf.AppendString(indent)
+	buf.AppendString(label)
+	buf.AppendString(" (")
+	buf.AppendString(errorTypeName(err))
+	buf.AppendString("): ")
+	msg := err.Error()
+	if i := strings.IndexByte(msg, '\n'); i != -1 {
+		// Encode the rest lines of a multi-line message with a deeper indent.
+		buf.AppendString(msg[:i])
+		buf.AppendString(e.lineEnding)
+		e.encodeLines(buf, indent+textIndent, msg[i+1:])
+	} else {
+		buf.AppendString(msg)
+		buf.AppendString(e.lineEnding)
+	}
+	causes, causeChained := errorCausesOf(err, chained)
+	for _, cause := range causes {
+		if cause != nil {
+			e.encodeError(buf, indent+textIndent, "caused by", cause, causeChained)
+		}
+	}
+	if chained {
+		return
+	}
+	if stack := errorStack(err); stack != "" {
+		buf.AppendString(indent + textIndent)
+		buf.AppendString("stack:")
+		buf.AppendString(e.lineEnding)
+		e.encodeLines(buf, indent+textIndent+textIndent, stack)
+	}
+}
+
+// encodeLines encodes the text to the buf line by line with the indent.
+func (e *TextEncoder) encodeLines(buf *Buffer, indent string, text string) {
+	for len(text) > 0 {
+		i := strings.IndexByte(text, '\n')
+		if i == -1 {
+			i = len(text)
+		}
+		buf.AppendString(indent)
+		buf.AppendString(text[:i])
+		buf.AppendString(e.lineEnding)
+		if i == len(text) {
+			return
+		}
+		text = text[i+1:]
+	}
+}
+
+// textFieldEncoder wraps the TextEncoder to encode the values of the fields,
+// it collects the errors in the values with their labels, such as "err", "errs[0]" and "req.err",
+// to encode them line by line after the fields.
+type textFieldEncoder struct {
+	*TextEncoder
+	// path is the labels from the field to the current value, such as "req", ".err" and "[0]".
+	path []string
+	errs []textFieldError
+}
+
+type textFieldError struct {
+	label string
+	err   error
+}
+
+func (e *textFieldEncoder) AppendError(buf *Buffer, val error) {
+	e.TextEncoder.AppendError(buf, val)
+	e.errs = append(e.errs, textFieldError{label: strings.Join(e.path, ""), err: val})
+}
+
+func (e *textFieldEncoder) AppendArray(buf *Buffer, val ArrayEncoder) {
+	buf.AppendByte('[')
+	e.path = append(e.path, "")
+	for i, size := 0, val.Size(); i < size; i++ {
+		if i > 0 {
+			buf.AppendByte(' ')
+		}
+		e.path[len(e.path)-1] = "[" + strconv.Itoa(i) + "]"
+		val.AppendEle(e, buf, i)
+	}
+	e.path = e.path[:len(e.path)-1]
+	buf.AppendByte(']')
+}
+
+func (e *textFieldEncoder) AppendObject(buf *Buffer, val FieldVal) {
+	val.Encode(e, buf)
+}
+
+func (e *textFieldEncoder) AppendObjectStart(buf *Buffer) {
+	e.TextEncoder.AppendObjectStart(buf)
+	e.path = append(e.path, "")
+}
+
+func (e *textFieldEncoder) AppendObjectKey(buf *Buffer, key string, i int) {
+	e.TextEncoder.AppendObjectKey(buf, key, i)
+	e.path[len(e.path)-1] = "." + key
+}
+
+func (e *textFieldEncoder) AppendObjectEnd(buf *Buffer) {
+	e.path = e.path[:len(e.path)-1]
+	e.TextEncoder.AppendObjectEnd(buf)
+}
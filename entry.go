@@ -1,6 +1,9 @@
 package wlog
 
 import (
+	"runtime"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 )
@@ -29,12 +32,70 @@ type Entry struct {
 	Level Level
 	Time  time.Time
 	Msg   string
+	// Caller is the location where the log message is emitted.
+	// It's only defined if the caller is enabled for the Logger.
+	Caller EntryCaller
+	// Stack is the stack trace of the goroutine that emits the log message.
+	// It's only set if the stack trace is enabled for the level of the log message.
+	Stack string
 }
 
 // Set sets the partial fields of the entry with the given lvl and msg
 // and sets the log time to the current time by default.
+// The other fields are reset to their zero values.
 func (e *Entry) Set(lvl Level, msg string) {
 	e.Level = lvl
 	e.Time = time.Now()
 	e.Msg = msg
+	e.Caller = EntryCaller{}
+	e.Stack = ""
+}
+
+// EntryCaller represents the caller of a logging method.
+type EntryCaller struct {
+	// Defined indicates whether the caller is available.
+	Defined  bool
+	PC       uintptr
+	File     string
+	Line     int
+	Function string
+}
+
+// NewEntryCaller returns a EntryCaller with the results of runtime.Caller.
+func NewEntryCaller(pc uintptr, file string, line int, ok bool) EntryCaller {
+	if !ok {
+		return EntryCaller{}
+	}
+	c := EntryCaller{Defined: true, PC: pc, File: file, Line: line}
+	if fn := runtime.FuncForPC(pc); fn != nil {
+		c.Function = fn.Name()
+	}
+	return c
+}
+
+// String returns the full path and line number of the caller, such as "/a/b/c/d.go:10".
+func (c EntryCaller) String() string {
+	if !c.Defined {
+		return "undefined"
+	}
+	return c.File + ":" + strconv.Itoa(c.Line)
+}
+
+// TrimmedPath returns the package directory, file name and line number of the caller,
+// such as "c/d.go:10".
+func (c EntryCaller) TrimmedPath() string {
+	if !c.Defined {
+		return "undefined"
+	}
+	// Find the last separator.
+	idx := strings.LastIndexByte(c.File, '/')
+	if idx == -1 {
+		return c.String()
+	}
+	// Find the penultimate separator.
+	idx = strings.LastIndexByte(c.File[:idx], '/')
+	if idx == -1 {
+		return c.String()
+	}
+	return c.File[idx+1:] + ":" + strconv.Itoa(c.Line)
 }
@@ -4,6 +4,9 @@ import (
 	"unsafe"
 	"time"
 	"fmt"
+	"reflect"
+	"encoding"
+	"encoding/json"
 )
 
 const (
@@ -88,6 +91,14 @@ func (v TimeVal) Encode(enc ObjEncoder, buf *Buffer) {
 	enc.AppendTime(buf, time.Time(v))
 }
 
+type ErrorVal struct {
+	Err error
+}
+
+func (v ErrorVal) Encode(enc ObjEncoder, buf *Buffer) {
+	enc.AppendError(buf, v.Err)
+}
+
 // Bool Returns a Field with the given key and value.
 func Bool(key string, val bool) Field {
 	return Field{Key: key, Val: BoolVal(val)}
@@ -183,10 +194,10 @@ func ByteString(key string, val []byte) Field {
 	return Field{Key: key, Val: ByteStringVal(val)}
 }
 
-// Ptr Returns a Field with the given key and value.
-// // It outputs the val.String() when logging the val.
+// Duration Returns a Field with the given key and value.
+// The val is encoded by the DurationEncoder of the Encoder, it's val.String() by default.
 func Duration(key string, val time.Duration) Field {
-	return String(key, val.String())
+	return Field{Key: key, Val: DurationVal(val)}
 }
 
 // Ptr Returns a Field with the given key and value and layout.
@@ -196,13 +207,13 @@ func Time(key string, val time.Time) Field {
 }
 
 // Err Returns a Field with the given key and err.
-// If the given err is nil, then output "<nil>" when logging the err,
-// otherwise output err.Error().
+// If the given err is nil, then output "<nil>" when logging the err, otherwise output
+// the message, the unwrap chain, the type name and the stack of the err according to the Encoder.
 func Err(key string, err error) Field {
 	if err == nil {
 		return String(key, nilStr)
 	}
-	return String(key, err.Error())
+	return Field{Key: key, Val: ErrorVal{Err: err}}
 }
 
 // Object Returns a Field with the given key and value.
@@ -321,8 +332,12 @@ func Ptr(key string, val unsafe.Pointer) Field {
 }
 
 // Interface Returns a Field with the given key and value.
-// It use the type assertion to construct a Field first,
-// if necessary, it will construct a Field by fmt.s Sprint method finally.
+// It use the type assertion to construct a Field first, then the json.Marshaler, encoding.TextMarshaler
+// and fmt.Stringer are detected, then the structs, maps, slices, arrays and pointers are encoded
+// by the Reflect, and it will construct a Field by fmt.Sprint finally.
+//
+// Note that a []byte is encoded as a string like the ByteString, and a pointer to a value without
+// a specified type assertion, such as *int, is dereferenced by the Reflect instead of formatted as an address.
 func Interface(key string, val interface{}) Field {
 	//return String(key,"==========================================")
 	switch v := val.(type) {
@@ -337,7 +352,7 @@ func Interface(key string, val interface{}) Field {
 	case *uint8:
 		return Uint8Ptr(key, v)
 	case []uint8:
-		return Uint8s(key, v)
+		return ByteString(key, v)
 	case int8:
 		return Int8(key, v)
 	case *int8:
@@ -430,11 +445,29 @@ func Interface(key string, val interface{}) Field {
 		return Err(key, v)
 	case []error:
 		return Errs(key, v)
+	case map[string]interface{}:
+		return Map(key, v)
+	case ObjectMarshaler:
+		return Marshaler(key, v)
 	}
 
 	if fv, ok := val.(FieldVal); ok {
 		return Field{Key: key, Val: fv}
 	}
 
+	switch v := val.(type) {
+	case json.Marshaler:
+		return JSONMarshaler(key, v)
+	case encoding.TextMarshaler:
+		return TextMarshaler(key, v)
+	case fmt.Stringer:
+		return Stringer(key, v)
+	}
+
+	switch reflect.ValueOf(val).Kind() {
+	case reflect.Struct, reflect.Map, reflect.Slice, reflect.Array, reflect.Ptr:
+		return Reflect(key, val)
+	}
+
 	return Field{Key: key, Val: StringVal(fmt.Sprint(val))}
 }
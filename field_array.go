@@ -194,7 +194,7 @@ func (vs errs) Size() int {
 func (vs errs) AppendEle(enc ObjEncoder, buf *Buffer, i int) {
 	err := vs[i]
 	if err != nil {
-		enc.AppendString(buf, err.Error())
+		enc.AppendError(buf, err)
 	} else {
 		enc.AppendString(buf, nilStr)
 	}
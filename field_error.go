@@ -0,0 +1,267 @@
+package wlog
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+)
+
+// multiUnwrapper is implemented by the errors that wrap multiple errors, such as the errors.Join.
+type multiUnwrapper interface {
+	Unwrap() []error
+}
+
+// errorObj encodes an error as an object with its message, type name, causes and stack, such as:
+//
+//	{"msg":"query failed: timeout","type":"*fmt.wrapError","causes":[{"msg":"timeout","type":"*errors.errorString"}]}
+type errorObj struct {
+	err error
+	// chained is true if the err is a cause in the unwrap chain of another error,
+	// its causes are omitted because they are the following elements of the chain,
+	// unless the err wraps multiple errors.
+	chained bool
+}
+
+func (o errorObj) MarshalLogObject(enc ObjectEncoder) {
+	enc.AddString("msg", o.err.Error())
+	enc.AddString("type", errorTypeName(o.err))
+	if causes, chained := errorCausesOf(o.err, o.chained); len(causes) > 0 {
+		enc.AddArray("causes", errorObjs{errs: causes, chained: chained})
+	}
+	if !o.chained {
+		if stack := errorStack(o.err); stack != "" {
+			enc.AddString("stack", stack)
+		}
+	}
+}
+
+// errorObjs encodes the errors as an array of the errorObj.
+type errorObjs struct {
+	errs    []error
+	chained bool
+}
+
+func (vs errorObjs) Size() int {
+	return len(vs.errs)
+}
+
+func (vs errorObjs) AppendEle(enc ObjEncoder, buf *Buffer, i int) {
+	if vs.errs[i] == nil {
+		enc.AppendString(buf, nilStr)
+		return
+	}
+	appendObject(enc, buf, errorObj{err: vs.errs[i], chained: vs.chained})
+}
+
+// errorCausesOf returns the causes of the err that should be encoded with it
+// and whether the causes are a chain, the causes of a chained err are omitted unless it wraps multiple errors.
+func errorCausesOf(err error, chained bool) ([]error, bool) {
+	if m, ok := err.(multiUnwrapper); ok {
+		return m.Unwrap(), false
+	}
+	if chained {
+		return nil, false
+	}
+	return errorCauses(err), true
+}
+
+// errorCauses returns the unwrap chain of the err, or the wrapped errors if the err wraps multiple errors.
+// The chain stops at an error that wraps multiple errors, which is the last element of the chain.
+func errorCauses(err error) []error {
+	if m, ok := err.(multiUnwrapper); ok {
+		return m.Unwrap()
+	}
+	var causes []error
+	for {
+		u, ok := err.(interface{ Unwrap() error })
+		if !ok {
+			return causes
+		}
+		if err = u.Unwrap(); err == nil {
+			return causes
+		}
+		causes = append(causes, err)
+		if _, ok := err.(multiUnwrapper); ok {
+			return causes
+		}
+	}
+}
+
+// errorTypeName returns the name of the concrete type of the err, such as "*errors.errorString".
+func errorTypeName(err error) string {
+	return reflect.TypeOf(err).String()
+}
+
+// errorStack returns the verbose text formatted by "%+v" of the first error that carries a stack
+// in the unwrap chain of the err, such as the message with the stack trace of the github.com/pkg/errors,
+// so that the stack of a cause wrapped by fmt.Errorf("...: %w", err) is not lost.
+// The chain stops at an error that wraps multiple errors, whose errors are encoded with their own stacks.
+// It returns an empty string if no error in the chain implements the fmt.Formatter
+// or has nothing more than its message.
+func errorStack(err error) string {
+	for err != nil {
+		if _, ok := err.(fmt.Formatter); ok {
+			if verbose := fmt.Sprintf("%+v", err); verbose != err.Error() {
+				return verbose
+			}
+		}
+		u, ok := err.(interface{ Unwrap() error })
+		if !ok {
+			return ""
+		}
+		err = u.Unwrap()
+	}
+	return ""
+}
+
+// FieldsError is an error that carries the fields to be logged with it,
+// the fields of all FieldsErrors in the unwrap chain are added to the log by the BaseHandler
+// when the error is logged by the Err, Errs or Interface, including the fields added by the With.
+// The errors nested in other values, such as an object or a struct, are not inspected.
+type FieldsError struct {
+	msg    string
+	err    error
+	fields []Field
+}
+
+// WrapErr returns a FieldsError that wraps the err with the msg and carries the fields,
+// its message is in the form of "msg: err.Error()". It returns nil if the err is nil.
+func WrapErr(err error, msg string, fields ...Field) error {
+	if err == nil {
+		return nil
+	}
+	return &FieldsError{msg: msg, err: err, fields: fields}
+}
+
+func (e *FieldsError) Error() string {
+	if e.msg == "" {
+		return e.err.Error()
+	}
+	return e.msg + ": " + e.err.Error()
+}
+
+// Format formats e by the verb, "%+v" formats the wrapped error by "%+v" too,
+// so that the stack carried by the wrapped error is kept, such as the github.com/pkg/errors.
+func (e *FieldsError) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			if e.msg != "" {
+				io.WriteString(s, e.msg+": ")
+			}
+			fmt.Fprintf(s, "%+v", e.err)
+			return
+		}
+		io.WriteString(s, e.Error())
+	case 's':
+		io.WriteString(s, e.Error())
+	case 'q':
+		fmt.Fprintf(s, "%q", e.Error())
+	default:
+		// Report the unsupported verb in the same way as the fmt, such as "%!d(*wlog.FieldsError=msg)".
+		fmt.Fprintf(s, "%%!%c(%T=%s)", verb, e, e.Error())
+	}
+}
+
+// Unwrap returns the wrapped error.
+func (e *FieldsError) Unwrap() error {
+	return e.err
+}
+
+// Fields returns the fields carried by e, excluding the fields of the wrapped errors.
+func (e *FieldsError) Fields() []Field {
+	return e.fields
+}
+
+// fieldsCarrier is implemented by the errors that carry the fields, such as the FieldsError.
+type fieldsCarrier interface {
+	Fields() []Field
+}
+
+// ErrorFields returns the fields carried by all errors in the unwrap chain of the err,
+// including the errors wrapped by the errors.Join. If multiple errors carry the fields
+// with the same key, the field of the outermost error is returned.
+func ErrorFields(err error) []Field {
+	var fields []Field
+	walkErrors(err, func(err error) {
+		if c, ok := err.(fieldsCarrier); ok {
+			for _, field := range c.Fields() {
+				if !containsKey(fields, field.Key) {
+					fields = append(fields, field)
+				}
+			}
+		}
+	})
+	return fields
+}
+
+// walkErrors calls the fn for the err and all errors wrapped by it in the depth-first order.
+func walkErrors(err error, fn func(err error)) {
+	for err != nil {
+		fn(err)
+		switch x := err.(type) {
+		case multiUnwrapper:
+			for _, e := range x.Unwrap() {
+				walkErrors(e, fn)
+			}
+			return
+		case interface{ Unwrap() error }:
+			err = x.Unwrap()
+		default:
+			return
+		}
+	}
+}
+
+// expandErrorFields returns the fields with the fields carried by the errors of the ErrorVals
+// and the arrays of errors added, the carried fields whose keys already exist are skipped.
+// The carried fields are inserted before the first namespace field, so that they are never nested
+// in a namespace. The fields are returned directly if no error carries any field,
+// otherwise a new slice is returned and the given fields are never modified.
+func expandErrorFields(fields []Field) []Field {
+	// top is the number of the fields before the first namespace field.
+	top := 0
+	for top < len(fields) {
+		if _, ok := fields[top].Val.(NamespaceVal); ok {
+			break
+		}
+		top++
+	}
+	var carried []Field
+	expand := func(err error) {
+		for _, ef := range ErrorFields(err) {
+			if !containsKey(fields[:top], ef.Key) && !containsKey(carried, ef.Key) {
+				carried = append(carried, ef)
+			}
+		}
+	}
+	for _, field := range fields {
+		switch v := field.Val.(type) {
+		case ErrorVal:
+			expand(v.Err)
+		case ArrayVal:
+			if vs, ok := v.Val.(errs); ok {
+				for _, err := range vs {
+					expand(err)
+				}
+			}
+		}
+	}
+	if len(carried) == 0 {
+		return fields
+	}
+	res := make([]Field, 0, len(fields)+len(carried))
+	res = append(res, fields[:top]...)
+	res = append(res, carried...)
+	return append(res, fields[top:]...)
+}
+
+// containsKey returns true if any of the fields has the key.
+func containsKey(fields []Field, key string) bool {
+	for _, field := range fields {
+		if field.Key == key {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,155 @@
+package wlog
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testStackErr is an error with a stack like the github.com/pkg/errors.
+type testStackErr struct {
+	msg string
+}
+
+func (e *testStackErr) Error() string {
+	return e.msg
+}
+
+func (e *testStackErr) Format(s fmt.State, verb rune) {
+	io.WriteString(s, e.msg)
+	if verb == 'v' && s.Flag('+') {
+		io.WriteString(s, "\nmain.query\n\t/app/main.go:10")
+	}
+}
+
+func TestErrorField(t *testing.T) {
+	base := errors.New("timeout")
+	wrapped := fmt.Errorf("query failed: %w", fmt.Errorf("conn: %w", base))
+	joined := errors.Join(wrapped, &testStackErr{msg: "closed"})
+
+	je := NewJsonEncoder(SetLevelKey(""), DisableTime(), SetMsgKey(""))
+	out := encodeFields(je, Err("err", wrapped))
+	assert.Equal(t, `{"err":{"msg":"query failed: conn: timeout","type":"*fmt.wrapError","causes":[`+
+		`{"msg":"conn: timeout","type":"*fmt.wrapError"},{"msg":"timeout","type":"*errors.errorString"}]}}`+"\n", out)
+
+	out = encodeFields(je, Errs("errs", []error{joined, nil}), Interface("stack", &testStackErr{msg: "closed"}))
+	assert.Equal(t, `{"errs":[{"msg":"query failed: conn: timeout\nclosed","type":"*errors.joinError","causes":[`+
+		`{"msg":"query failed: conn: timeout","type":"*fmt.wrapError","causes":[`+
+		`{"msg":"conn: timeout","type":"*fmt.wrapError"},{"msg":"timeout","type":"*errors.errorString"}]},`+
+		`{"msg":"closed","type":"*wlog.testStackErr","stack":"closed\nmain.query\n\t/app/main.go:10"}]},"<nil>"],`+
+		`"stack":{"msg":"closed","type":"*wlog.testStackErr","stack":"closed\nmain.query\n\t/app/main.go:10"}}`+"\n", out)
+	assert.True(t, json.Valid([]byte(out)))
+
+	te := NewTextEncoder(SetLevelKey(""), DisableTime())
+	assert.Equal(t, "failed  [err=query failed: conn: timeout]\n"+
+		"    err (*fmt.wrapError): query failed: conn: timeout\n"+
+		"        caused by (*fmt.wrapError): conn: timeout\n"+
+		"        caused by (*errors.errorString): timeout\n",
+		encodeEntry(te, "failed", Err("err", wrapped)))
+	assert.Equal(t, "failed  [errs=[query failed: conn: timeout\\nclosed <nil>]]\n"+
+		"    errs[0] (*errors.joinError): query failed: conn: timeout\n"+
+		"        closed\n"+
+		"        caused by (*fmt.wrapError): query failed: conn: timeout\n"+
+		"            caused by (*fmt.wrapError): conn: timeout\n"+
+		"            caused by (*errors.errorString): timeout\n"+
+		"        caused by (*wlog.testStackErr): closed\n"+
+		"            stack:\n"+
+		"                closed\n"+
+		"                main.query\n"+
+		"                \t/app/main.go:10\n",
+		encodeEntry(te, "failed", Errs("errs", []error{joined, nil})))
+}
+
+func TestTextEncoderNestedErrors(t *testing.T) {
+	te := NewTextEncoder(SetLevelKey(""), DisableTime())
+	err := fmt.Errorf("w: %w", errors.New("x"))
+	assert.Equal(t, "failed  [d={e=w: x} http.err=w: x http.errs=[<nil> w: x]]\n"+
+		"    d.e (*fmt.wrapError): w: x\n"+
+		"        caused by (*errors.errorString): x\n"+
+		"    http.err (*fmt.wrapError): w: x\n"+
+		"        caused by (*errors.errorString): x\n"+
+		"    http.errs[1] (*fmt.wrapError): w: x\n"+
+		"        caused by (*errors.errorString): x\n",
+		encodeEntry(te, "failed", Dict("d", Err("e", err)), Namespace("http"), Err("err", err),
+			Errs("errs", []error{nil, err})))
+}
+
+func TestErrorStackInChain(t *testing.T) {
+	err := fmt.Errorf("query: %w", &testStackErr{msg: "closed"})
+
+	je := NewJsonEncoder(SetLevelKey(""), DisableTime(), SetMsgKey(""))
+	out := encodeFields(je, Err("err", err))
+	assert.Equal(t, `{"err":{"msg":"query: closed","type":"*fmt.wrapError","causes":[`+
+		`{"msg":"closed","type":"*wlog.testStackErr"}],"stack":"closed\nmain.query\n\t/app/main.go:10"}}`+"\n", out)
+	assert.True(t, json.Valid([]byte(out)))
+
+	te := NewTextEncoder(SetLevelKey(""), DisableTime())
+	assert.Equal(t, "failed  [err=query: closed]\n"+
+		"    err (*fmt.wrapError): query: closed\n"+
+		"        caused by (*wlog.testStackErr): closed\n"+
+		"        stack:\n"+
+		"            closed\n"+
+		"            main.query\n"+
+		"            \t/app/main.go:10\n",
+		encodeEntry(te, "failed", Err("err", err)))
+}
+
+func encodeEntry(e Encoder, msg string, fields ...Field) string {
+	buf := GetBuf()
+	defer PutBuf(buf)
+	e.Encode(buf, &Entry{Msg: msg}, fields...)
+	return buf.String()
+}
+
+func TestWrapErr(t *testing.T) {
+	assert.Nil(t, WrapErr(nil, "query failed"))
+
+	base := WrapErr(errors.New("timeout"), "dial", String("addr", "db:3306"), String("table", "inner"))
+	err := WrapErr(fmt.Errorf("retry: %w", base), "query failed", String("table", "users"))
+	assert.Equal(t, "query failed: retry: dial: timeout", err.Error())
+	assert.True(t, errors.Is(err, base))
+	assert.Equal(t, []Field{String("table", "users")}, err.(*FieldsError).Fields())
+	assert.Equal(t, []Field{String("table", "users"), String("addr", "db:3306")}, ErrorFields(err))
+
+	buf := &bytes.Buffer{}
+	logger := NewLogger(newTestHandler(buf))
+	logger.Errorw("failed", Interface("err", err), String("addr", "db:5432"))
+	assert.Equal(t, "[ERROR]  failed  [err=query failed: retry: dial: timeout addr=db:5432 table=users]\n",
+		strings.SplitAfter(buf.String(), "\n")[0])
+
+	buf.Reset()
+	joined := errors.Join(WrapErr(errors.New("a"), "", Int("id", 1)), WrapErr(errors.New("b"), "", Int("id", 2), Int("code", 3)))
+	logger.Errorw("failed", Err("err", joined))
+	assert.Equal(t, "[ERROR]  failed  [err=a\\nb id=1 code=3]\n", strings.SplitAfter(buf.String(), "\n")[0])
+
+	// The errors of the arrays and the errors added by the With carry their fields too.
+	buf.Reset()
+	logger.With(Err("cause", base)).Errorw("failed", Errs("errs", []error{nil, err}))
+	assert.Equal(t, "[ERROR]  failed  [cause=dial: timeout errs=[<nil> query failed: retry: dial: timeout] "+
+		"addr=db:3306 table=inner]\n", strings.SplitAfter(buf.String(), "\n")[0])
+
+	// The carried fields are never nested in a namespace.
+	je := NewJsonEncoder(SetLevelKey(""), DisableTime(), SetMsgKey(""))
+	buf.Reset()
+	NewLogger(NewBaseHandler(NewIOWriter(buf), je)).Errorw("", String("table", "t"), Namespace("http"),
+		String("addr", "a"), Err("err", base))
+	assert.Equal(t, `{"table":"t","addr":"db:3306","http":{"addr":"a","err":{"msg":"dial: timeout","type":"*wlog.FieldsError",`+
+		`"causes":[{"msg":"timeout","type":"*errors.errorString"}]}}}`+"\n", buf.String())
+
+	// The unsupported verbs are reported in the same way as the fmt.
+	assert.Equal(t, "%!d(*wlog.FieldsError=dial: timeout)", fmt.Sprintf("%d", base))
+
+	// The stack of the wrapped error is kept.
+	stackErr := WrapErr(&testStackErr{msg: "closed"}, "query", Int("id", 1))
+	assert.Equal(t, "query: closed", fmt.Sprintf("%v", stackErr))
+	assert.Equal(t, "query: closed\nmain.query\n\t/app/main.go:10", fmt.Sprintf("%+v", stackErr))
+	assert.Equal(t, `{"err":{"msg":"query: closed","type":"*wlog.FieldsError","causes":[`+
+		`{"msg":"closed","type":"*wlog.testStackErr"}],"stack":"query: closed\nmain.query\n\t/app/main.go:10"}}`+"\n",
+		encodeFields(je, Err("err", stackErr)))
+}
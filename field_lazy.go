@@ -0,0 +1,59 @@
+package wlog
+
+import "fmt"
+
+// lazyFieldsKey is the key of the field returned by the LazyFields.
+const lazyFieldsKey = "lazyFields"
+
+type LazyVal func() interface{}
+
+func (v LazyVal) Encode(enc ObjEncoder, buf *Buffer) {
+	encodeMarshalerVal(enc, buf, v)
+}
+
+func (v LazyVal) marshal() (FieldVal, error) {
+	return safeMarshal(v, func() (FieldVal, error) {
+		val := Interface("", v()).Val
+		// The value may be another lazy value, such as the value of the Stringer.
+		if mv, ok := val.(marshalerVal); ok {
+			return mv.marshal()
+		}
+		return val, nil
+	})
+}
+
+type LazyFieldsVal func() []Field
+
+// Encode encodes the fields as an object if the v is not expanded before encoding.
+func (v LazyFieldsVal) Encode(enc ObjEncoder, buf *Buffer) {
+	fields, err := v.fields()
+	if err != nil {
+		enc.AppendError(buf, err)
+		return
+	}
+	appendObject(enc, buf, dict(fields))
+}
+
+// fields returns the fields returned by the v, and converts a panic of the v to an error.
+func (v LazyFieldsVal) fields() (fields []Field, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			fields, err = nil, fmt.Errorf("panic when getting lazy fields: %v", r)
+		}
+	}()
+	return v(), nil
+}
+
+// Lazy Returns a Field with the given key and the function to get the value.
+// The fn is called only when the log is written, and the value returned by it
+// is encoded in the same way as the Interface.
+func Lazy(key string, fn func() interface{}) Field {
+	return Field{Key: key, Val: LazyVal(fn)}
+}
+
+// LazyFields Returns a Field that is expanded to the fields returned by the fn when the log is written,
+// so the fn is called only when the log is written.
+// Note that: the fn may be called by every Handler that writes the log, such as the TeeHandler.
+func LazyFields(fn func() []Field) Field {
+	return Field{Key: lazyFieldsKey, Val: LazyFieldsVal(fn)}
+}
@@ -0,0 +1,190 @@
+package wlog
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// marshalErrKeySuffix is the suffix of the key of the field that reports the error of marshaling a value.
+const marshalErrKeySuffix = "Error"
+
+// marshalerVal is implemented by the values that are marshaled lazily when they are encoded,
+// such as the values of the Stringer, JSONMarshaler and TextMarshaler.
+type marshalerVal interface {
+	FieldVal
+	// marshal returns the marshaled value.
+	marshal() (FieldVal, error)
+}
+
+// encodeMarshalerVal encodes the marshaled value of the v, or the error if it fails to be marshaled.
+func encodeMarshalerVal(enc ObjEncoder, buf *Buffer, v marshalerVal) {
+	val, err := v.marshal()
+	if err != nil {
+		enc.AppendError(buf, err)
+		return
+	}
+	val.Encode(enc, buf)
+}
+
+// safeMarshal returns the value marshaled by the fn, it returns "<nil>" if the v is a nil pointer
+// and converts a panic of the fn to an error.
+func safeMarshal(v interface{}, fn func() (FieldVal, error)) (val FieldVal, err error) {
+	if rv := reflect.ValueOf(v); rv.Kind() == reflect.Ptr && rv.IsNil() {
+		return StringVal(nilStr), nil
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			val, err = nil, fmt.Errorf("panic when marshaling %T: %v", v, r)
+		}
+	}()
+	return fn()
+}
+
+// marshalFields returns the fields with the values of the marshalerVals marshaled and the LazyFieldsVals
+// expanded, and a field whose value fails to be marshaled is replaced with a field with the key "<key>Error"
+// and the error. The fields are returned directly if there is no such value in them,
+// otherwise a new slice is returned and the given fields are never modified.
+func marshalFields(fields []Field) []Field {
+	var res []Field
+	for i, field := range fields {
+		if !isLazyVal(field.Val) {
+			if res != nil {
+				res = append(res, field)
+			}
+			continue
+		}
+		if res == nil {
+			res = make([]Field, i, len(fields))
+			copy(res, fields[:i])
+		}
+		res = appendMarshaledField(res, field)
+	}
+	if res == nil {
+		return fields
+	}
+	return res
+}
+
+// appendMarshaledField appends the field to the res after marshaling or expanding its value if necessary.
+func appendMarshaledField(res []Field, field Field) []Field {
+	switch v := field.Val.(type) {
+	case LazyFieldsVal:
+		fields, err := v.fields()
+		if err != nil {
+			return append(res, Err(field.Key+marshalErrKeySuffix, err))
+		}
+		for _, f := range fields {
+			res = appendMarshaledField(res, f)
+		}
+		return res
+	case marshalerVal:
+		val, err := v.marshal()
+		if err != nil {
+			return append(res, Err(field.Key+marshalErrKeySuffix, err))
+		}
+		return append(res, Field{Key: field.Key, Val: val})
+	}
+	return append(res, field)
+}
+
+// isLazyVal returns true if the val is a marshalerVal or a LazyFieldsVal.
+func isLazyVal(val FieldVal) bool {
+	switch val.(type) {
+	case marshalerVal, LazyFieldsVal:
+		return true
+	}
+	return false
+}
+
+// RawJSONVal is a valid and compact JSON, it's embedded in the JsonEncoder directly,
+// and it's encoded as a quoted string by the other Encoders.
+type RawJSONVal []byte
+
+func (v RawJSONVal) Encode(enc ObjEncoder, buf *Buffer) {
+	enc.AppendJSON(buf, v)
+}
+
+type StringerVal struct {
+	V fmt.Stringer
+}
+
+func (v StringerVal) Encode(enc ObjEncoder, buf *Buffer) {
+	encodeMarshalerVal(enc, buf, v)
+}
+
+func (v StringerVal) marshal() (FieldVal, error) {
+	return safeMarshal(v.V, func() (FieldVal, error) {
+		return StringVal(v.V.String()), nil
+	})
+}
+
+type JSONMarshalerVal struct {
+	V json.Marshaler
+}
+
+func (v JSONMarshalerVal) Encode(enc ObjEncoder, buf *Buffer) {
+	encodeMarshalerVal(enc, buf, v)
+}
+
+func (v JSONMarshalerVal) marshal() (FieldVal, error) {
+	return safeMarshal(v.V, func() (FieldVal, error) {
+		// The json.Marshal validates and compacts the output of the MarshalJSON.
+		bs, err := json.Marshal(v.V)
+		if err != nil {
+			return nil, err
+		}
+		return RawJSONVal(bs), nil
+	})
+}
+
+type TextMarshalerVal struct {
+	V encoding.TextMarshaler
+}
+
+func (v TextMarshalerVal) Encode(enc ObjEncoder, buf *Buffer) {
+	encodeMarshalerVal(enc, buf, v)
+}
+
+func (v TextMarshalerVal) marshal() (FieldVal, error) {
+	return safeMarshal(v.V, func() (FieldVal, error) {
+		bs, err := v.V.MarshalText()
+		if err != nil {
+			return nil, err
+		}
+		return ByteStringVal(bs), nil
+	})
+}
+
+// Stringer Returns a Field with the given key and value.
+// The val.String() is called lazily when the val is encoded.
+// If the given val is nil, then output "<nil>" when logging the val.
+func Stringer(key string, val fmt.Stringer) Field {
+	if val == nil {
+		return String(key, nilStr)
+	}
+	return Field{Key: key, Val: StringerVal{V: val}}
+}
+
+// JSONMarshaler Returns a Field with the given key and value.
+// The val is marshaled lazily when the val is encoded, it's embedded as a raw JSON by the JsonEncoder,
+// and it's encoded as a quoted string by the other Encoders. If it fails to marshal the val,
+// then output the error with the key "<key>Error" instead. If the given val is nil, then output "<nil>".
+func JSONMarshaler(key string, val json.Marshaler) Field {
+	if val == nil {
+		return String(key, nilStr)
+	}
+	return Field{Key: key, Val: JSONMarshalerVal{V: val}}
+}
+
+// TextMarshaler Returns a Field with the given key and value.
+// The val is marshaled lazily when the val is encoded, and it's encoded as a string.
+// If it fails to marshal the val, then output the error with the key "<key>Error" instead.
+// If the given val is nil, then output "<nil>" when logging the val.
+func TextMarshaler(key string, val encoding.TextMarshaler) Field {
+	if val == nil {
+		return String(key, nilStr)
+	}
+	return Field{Key: key, Val: TextMarshalerVal{V: val}}
+}
@@ -0,0 +1,72 @@
+package wlog
+
+import (
+	"bytes"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testPoint struct {
+	X, Y int
+}
+
+func (p *testPoint) String() string {
+	return "point"
+}
+
+type testJSON struct {
+	raw string
+	err error
+}
+
+func (v testJSON) MarshalJSON() ([]byte, error) {
+	return []byte(v.raw), v.err
+}
+
+type testCounter struct {
+	n *int
+}
+
+func (c testCounter) String() string {
+	*c.n++
+	return "counter"
+}
+
+func TestMarshalerFields(t *testing.T) {
+	ip := net.ParseIP("127.0.0.1")
+	fields := []Field{
+		Stringer("point", &testPoint{X: 1}),
+		JSONMarshaler("raw", testJSON{raw: "{\"a\": [1, 2]}"}),
+		TextMarshaler("ip", ip),
+		Interface("ip2", ip),
+		Interface("point2", &testPoint{X: 1}),
+		Stringer("nil", (*testPoint)(nil)),
+	}
+	je := NewJsonEncoder(SetLevelKey(""), DisableTime(), SetMsgKey(""))
+	assert.Equal(t, `{"point":"point","raw":{"a":[1,2]},"ip":"127.0.0.1","ip2":"127.0.0.1","point2":"point","nil":"<nil>"}`+"\n",
+		encodeFields(je, fields...))
+	te := NewTextEncoder(SetLevelKey(""), DisableTime(), SetMsgKey(""))
+	assert.Equal(t, `[point=point raw="{\"a\":[1,2]}" ip=127.0.0.1 ip2=127.0.0.1 point2=point nil=<nil>]`+"\n",
+		encodeFields(te, fields...))
+	le := NewLogfmtEncoder(SetLevelKey(""), DisableTime(), SetMsgKey(""))
+	assert.Equal(t, `raw="{\"a\":[1,2]}"`+"\n", encodeFields(le, fields[1]))
+
+	// The value is marshaled lazily only if the log is written.
+	n := 0
+	buf := &bytes.Buffer{}
+	logger := NewLogger(NewBaseHandler(NewIOWriter(buf), je), SetLogMinLvl(InfoLvl))
+	logger.Debugw("dropped", Stringer("counter", testCounter{n: &n}))
+	assert.Equal(t, 0, n)
+	logger.Infow("written", Stringer("counter", testCounter{n: &n}))
+	assert.Equal(t, 1, n)
+
+	// The error of marshaling is reported as a field with the key "<key>Error".
+	buf.Reset()
+	logger.Infow("", JSONMarshaler("raw", testJSON{err: errors.New("bad")}), JSONMarshaler("invalid", testJSON{raw: "{"}))
+	assert.Contains(t, buf.String(), `"rawError":{"msg":"json: error calling MarshalJSON for type *wlog.testJSON: bad",`)
+	assert.Contains(t, buf.String(), `"invalidError":{"msg":"json: error calling MarshalJSON for type *wlog.testJSON: `)
+	assert.NotContains(t, buf.String(), `"raw":`)
+}
@@ -0,0 +1,196 @@
+package wlog
+
+import "time"
+
+// ObjectMarshaler interface is used to serialize a value as an object with the key-value pairs,
+// such as {"id":1,"name":"root"} for the JsonEncoder and {id=1 name=root} for the TextEncoder.
+type ObjectMarshaler interface {
+	// MarshalLogObject adds the key-value pairs of the object to the enc.
+	MarshalLogObject(enc ObjectEncoder)
+}
+
+// ObjectMarshalerFunc is a function that implements the ObjectMarshaler interface.
+type ObjectMarshalerFunc func(enc ObjectEncoder)
+
+func (f ObjectMarshalerFunc) MarshalLogObject(enc ObjectEncoder) {
+	f(enc)
+}
+
+// ObjectEncoder interface is used by an ObjectMarshaler to add the key-value pairs of an object.
+type ObjectEncoder interface {
+	AddBool(key string, val bool)
+	AddInt(key string, val int)
+	AddInt64(key string, val int64)
+	AddUint64(key string, val uint64)
+	AddFloat64(key string, val float64)
+	AddString(key, val string)
+	AddDuration(key string, val time.Duration)
+	AddTime(key string, val time.Time)
+	// AddArray adds an array with the given key.
+	AddArray(key string, val ArrayEncoder)
+	// AddObject adds a nested object with the given key.
+	AddObject(key string, val ObjectMarshaler)
+	// AddField adds the value of the field with its key.
+	AddField(field Field)
+}
+
+// objectEncoder implements the ObjectEncoder interface by the ObjEncoder.
+type objectEncoder struct {
+	enc ObjEncoder
+	buf *Buffer
+	// n is the number of the key-value pairs that have been added.
+	n int
+}
+
+func (o *objectEncoder) addKey(key string) {
+	o.enc.AppendObjectKey(o.buf, key, o.n)
+	o.n++
+}
+
+func (o *objectEncoder) AddBool(key string, val bool) {
+	o.addKey(key)
+	o.enc.AppendBool(o.buf, val)
+}
+
+func (o *objectEncoder) AddInt(key string, val int) {
+	o.addKey(key)
+	o.enc.AppendInt(o.buf, val)
+}
+
+func (o *objectEncoder) AddInt64(key string, val int64) {
+	o.addKey(key)
+	o.enc.AppendInt64(o.buf, val)
+}
+
+func (o *objectEncoder) AddUint64(key string, val uint64) {
+	o.addKey(key)
+	o.enc.AppendUint64(o.buf, val)
+}
+
+func (o *objectEncoder) AddFloat64(key string, val float64) {
+	o.addKey(key)
+	o.enc.AppendFloat64(o.buf, val)
+}
+
+func (o *objectEncoder) AddString(key, val string) {
+	o.addKey(key)
+	o.enc.AppendString(o.buf, val)
+}
+
+func (o *objectEncoder) AddDuration(key string, val time.Duration) {
+	o.addKey(key)
+	o.enc.AppendDuration(o.buf, val)
+}
+
+func (o *objectEncoder) AddTime(key string, val time.Time) {
+	o.addKey(key)
+	o.enc.AppendTime(o.buf, val)
+}
+
+func (o *objectEncoder) AddArray(key string, val ArrayEncoder) {
+	o.addKey(key)
+	o.enc.AppendArray(o.buf, val)
+}
+
+func (o *objectEncoder) AddObject(key string, val ObjectMarshaler) {
+	o.addKey(key)
+	appendObject(o.enc, o.buf, val)
+}
+
+func (o *objectEncoder) AddField(field Field) {
+	o.addKey(field.Key)
+	field.Val.Encode(o.enc, o.buf)
+}
+
+// appendObject appends the object marshaled by the m to the buf for the enc.
+func appendObject(enc ObjEncoder, buf *Buffer, m ObjectMarshaler) {
+	enc.AppendObjectStart(buf)
+	m.MarshalLogObject(&objectEncoder{enc: enc, buf: buf})
+	enc.AppendObjectEnd(buf)
+}
+
+type MarshalerVal struct {
+	Marshaler ObjectMarshaler
+}
+
+func (v MarshalerVal) Encode(enc ObjEncoder, buf *Buffer) {
+	appendObject(enc, buf, v.Marshaler)
+}
+
+// dict is an object with the key-value pairs of the fields in order.
+type dict []Field
+
+func (d dict) MarshalLogObject(enc ObjectEncoder) {
+	for _, field := range d {
+		enc.AddField(field)
+	}
+}
+
+// Marshaler Returns a Field with the given key and value.
+// The val is encoded as an object, it outputs "<nil>" if the val is nil.
+func Marshaler(key string, val ObjectMarshaler) Field {
+	if val == nil {
+		return String(key, nilStr)
+	}
+	return Field{Key: key, Val: MarshalerVal{Marshaler: val}}
+}
+
+// Dict Returns a Field with the given key and fields.
+// The fields are encoded as an object in order.
+func Dict(key string, fields ...Field) Field {
+	return Field{Key: key, Val: MarshalerVal{Marshaler: dict(fields)}}
+}
+
+// Map Returns a Field with the given key and value.
+// The val is encoded as an object in the ascending order of its keys by the Reflect,
+// so that a map that contains itself is encoded with a placeholder instead of recursing infinitely.
+// If the given val is nil, then output "<nil>" when logging the val.
+func Map(key string, val map[string]interface{}) Field {
+	if val == nil {
+		return String(key, nilStr)
+	}
+	return Field{Key: key, Val: ReflectVal{V: val}}
+}
+
+// NamespaceVal is the value of a namespace field, the fields after it are nested in the namespace.
+// It's encoded as an empty object if it's not encoded by an Encoder directly.
+type NamespaceVal struct{}
+
+func (v NamespaceVal) Encode(enc ObjEncoder, buf *Buffer) {
+	enc.AppendObjectStart(buf)
+	enc.AppendObjectEnd(buf)
+}
+
+// Namespace Returns a Field with the given key,
+// the fields after it are nested in an object with the key, such as {"http":{"status":200}},
+// or prefixed with the key and a dot, such as "http.status=200", according to the Encoder.
+func Namespace(key string) Field {
+	return Field{Key: key, Val: NamespaceVal{}}
+}
+
+// prefixNamespaces returns the fields without the namespace fields,
+// and every key of the returned fields is prefixed with the keys of all namespaces before it,
+// such as "http.status". The fields are returned directly if there is no namespace in them.
+func prefixNamespaces(fields []Field) []Field {
+	i := 0
+	for ; i < len(fields); i++ {
+		if _, ok := fields[i].Val.(NamespaceVal); ok {
+			break
+		}
+	}
+	if i == len(fields) {
+		return fields
+	}
+	res := make([]Field, i, len(fields)-1)
+	copy(res, fields[:i])
+	prefix := ""
+	for _, field := range fields[i:] {
+		if _, ok := field.Val.(NamespaceVal); ok {
+			prefix += field.Key + "."
+			continue
+		}
+		field.Key = prefix + field.Key
+		res = append(res, field)
+	}
+	return res
+}
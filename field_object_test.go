@@ -0,0 +1,85 @@
+package wlog
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testUser struct {
+	ID    int
+	Name  string
+	Roles []string
+}
+
+func (u testUser) MarshalLogObject(enc ObjectEncoder) {
+	enc.AddInt("id", u.ID)
+	enc.AddString("name", u.Name)
+	enc.AddArray("roles", strs(u.Roles))
+	enc.AddObject("meta", ObjectMarshalerFunc(func(enc ObjectEncoder) {
+		enc.AddDuration("ttl", time.Second)
+	}))
+}
+
+func encodeFields(e Encoder, fields ...Field) string {
+	buf := GetBuf()
+	defer PutBuf(buf)
+	e.Encode(buf, &Entry{}, fields...)
+	return buf.String()
+}
+
+func TestObjectFields(t *testing.T) {
+	user := testUser{ID: 1, Name: "x y", Roles: []string{"admin"}}
+	fields := []Field{
+		Marshaler("user", user),
+		Dict("req", String("method", "GET"), Int("status", 200), Dict("empty")),
+		Map("map", map[string]interface{}{"b": true, "a": 1.5, "c": map[string]interface{}{"d": "e"}}),
+		Interface("iface", user),
+	}
+
+	je := NewJsonEncoder(SetLevelKey(""), DisableTime(), SetMsgKey(""))
+	out := encodeFields(je, fields...)
+	assert.Equal(t, `{"user":{"id":1,"name":"x y","roles":["admin"],"meta":{"ttl":"1s"}},`+
+		`"req":{"method":"GET","status":200,"empty":{}},`+
+		`"map":{"a":1.5,"b":true,"c":{"d":"e"}},`+
+		`"iface":{"id":1,"name":"x y","roles":["admin"],"meta":{"ttl":"1s"}}}`+"\n", out)
+	assert.True(t, json.Valid([]byte(out)))
+
+	te := NewTextEncoder(SetLevelKey(""), DisableTime(), SetMsgKey(""))
+	assert.Equal(t, `[user={id=1 name=x y roles=[admin] meta={ttl=1s}} `+
+		`req={method=GET status=200 empty={}} `+
+		`map={a=1.5 b=true c={d=e}} `+
+		`iface={id=1 name=x y roles=[admin] meta={ttl=1s}}]`+"\n", encodeFields(te, fields...))
+
+	le := NewLogfmtEncoder(SetLevelKey(""), DisableTime(), SetMsgKey(""))
+	assert.Equal(t, `req="{method=GET status=200 empty={}}"`+"\n", encodeFields(le, fields[1]))
+
+	assert.Equal(t, `{"user":"<nil>"}`+"\n", encodeFields(je, Marshaler("user", nil)))
+}
+
+func TestNamespace(t *testing.T) {
+	buf := &bytes.Buffer{}
+	je := NewJsonEncoder(SetLevelKey(""), DisableTime(), SetMsgKey(""))
+	logger := NewLogger(NewBaseHandler(NewIOWriter(buf), je)).With(String("app", "wlog"), Namespace("http"))
+	logger = logger.With(String("method", "GET"))
+	logger.Infow("", Int("status", 200), Namespace("user"), String("name", "root"))
+	logger.Infow("")
+	assert.Equal(t, `{"app":"wlog","http":{"method":"GET","status":200,"user":{"name":"root"}}}`+"\n"+
+		`{"app":"wlog","http":{"method":"GET"}}`+"\n", buf.String())
+
+	buf.Reset()
+	logger = NewLogger(newTestHandler(buf)).With(String("app", "wlog"), Namespace("http"))
+	logger.With(String("method", "GET")).Infow("req", Int("status", 200), Namespace("user"), String("name", "root"))
+	assert.Equal(t, "[INFO]  req  [app=wlog http.method=GET http.status=200 http.user.name=root]\n", buf.String())
+
+	fields := []Field{Namespace("http"), Int("status", 200)}
+	le := NewLogfmtEncoder(SetLevelKey(""), DisableTime(), SetMsgKey(""))
+	assert.Equal(t, "http.status=200\n", encodeFields(le, fields...))
+	pe, err := NewPatternEncoder("%X{http.status} %fields")
+	assert.NoError(t, err)
+	assert.Equal(t, "200 ", encodeFields(pe, fields...))
+	assert.Equal(t, `{"d":{"http":{}}}`+"\n", encodeFields(je, Dict("d", Namespace("http"))))
+}
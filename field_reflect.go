@@ -0,0 +1,326 @@
+package wlog
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// reflectMaxDepth is the maximum depth of the nested objects and arrays encoded by reflection,
+	// the values deeper than it are replaced with reflectDepthStr.
+	reflectMaxDepth = 16
+	reflectDepthStr = "<max depth>"
+	// reflectCycleStr replaces the value of a pointer or a map that refers to one of its ancestors.
+	reflectCycleStr = "<cycle>"
+	// reflectMaxValues is the maximum number of the values encoded by reflection for a field,
+	// the values after it are replaced with reflectValuesStr. It limits the size of the output
+	// when the same values are referred to by many paths, which is not detected as a cycle.
+	reflectMaxValues = 10000
+	reflectValuesStr = "<max values>"
+)
+
+var (
+	timeType     = reflect.TypeOf(time.Time{})
+	durationType = reflect.TypeOf(time.Duration(0))
+	// reflectMarshalerTypes are the interfaces that take precedence over the reflection,
+	// a value implementing any of them is encoded in the same way as the Interface.
+	reflectMarshalerTypes = []reflect.Type{
+		reflect.TypeOf((*error)(nil)).Elem(),
+		reflect.TypeOf((*ObjectMarshaler)(nil)).Elem(),
+		reflect.TypeOf((*FieldVal)(nil)).Elem(),
+		reflect.TypeOf((*json.Marshaler)(nil)).Elem(),
+		reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem(),
+		reflect.TypeOf((*fmt.Stringer)(nil)).Elem(),
+	}
+)
+
+type ReflectVal struct {
+	V interface{}
+}
+
+func (v ReflectVal) Encode(enc ObjEncoder, buf *Buffer) {
+	st := &reflectState{}
+	st.encode(enc, buf, reflect.ValueOf(v.V))
+}
+
+// reflectState records the state of encoding a value by reflection.
+type reflectState struct {
+	// depth is the depth of the current value.
+	depth int
+	// visited are the addresses of the pointers and maps from the root to the current value.
+	visited []uintptr
+	// values is the number of the encoded values.
+	values int
+}
+
+func (st *reflectState) encode(enc ObjEncoder, buf *Buffer, v reflect.Value) {
+	if st.values >= reflectMaxValues {
+		enc.AppendString(buf, reflectValuesStr)
+		return
+	}
+	st.values++
+	if !v.IsValid() {
+		enc.AppendString(buf, nilStr)
+		return
+	}
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan:
+		if v.IsNil() {
+			enc.AppendString(buf, nilStr)
+			return
+		}
+	}
+	if v.CanInterface() && isReflectMarshaler(v.Type()) {
+		Interface("", v.Interface()).Val.Encode(enc, buf)
+		return
+	}
+	switch v.Kind() {
+	case reflect.Bool:
+		enc.AppendBool(buf, v.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if v.Type() == durationType {
+			enc.AppendDuration(buf, time.Duration(v.Int()))
+			return
+		}
+		enc.AppendInt64(buf, v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		enc.AppendUint64(buf, v.Uint())
+	case reflect.Float32, reflect.Float64:
+		enc.AppendFloat64(buf, v.Float())
+	case reflect.Complex64, reflect.Complex128:
+		enc.AppendComplex128(buf, v.Complex())
+	case reflect.String:
+		enc.AppendString(buf, v.String())
+	case reflect.Interface:
+		st.encode(enc, buf, v.Elem())
+	case reflect.Ptr:
+		if !st.enter(enc, buf, v.Pointer()) {
+			return
+		}
+		st.encode(enc, buf, v.Elem())
+		st.leave(true)
+	case reflect.Struct:
+		if v.Type() == timeType && v.CanInterface() {
+			enc.AppendTime(buf, v.Interface().(time.Time))
+			return
+		}
+		if !st.enter(enc, buf, 0) {
+			return
+		}
+		enc.AppendObjectStart(buf)
+		st.encodeStruct(enc, buf, v, 0)
+		enc.AppendObjectEnd(buf)
+		st.leave(false)
+	case reflect.Map:
+		if !st.enter(enc, buf, v.Pointer()) {
+			return
+		}
+		st.encodeMap(enc, buf, v)
+		st.leave(true)
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8 {
+			// Encode the bytes as a string like the ByteString.
+			enc.AppendByteString(buf, v.Bytes())
+			return
+		}
+		if !st.enter(enc, buf, 0) {
+			return
+		}
+		enc.AppendArray(buf, reflectArray{st: st, v: v})
+		st.leave(false)
+	default:
+		enc.AppendString(buf, fmt.Sprint(v))
+	}
+}
+
+// enter increases the depth before encoding a nested value, and records the ptr if it's not zero.
+// It returns false and encodes a placeholder instead if the depth is too deep or the ptr is visited.
+func (st *reflectState) enter(enc ObjEncoder, buf *Buffer, ptr uintptr) bool {
+	if st.depth >= reflectMaxDepth {
+		enc.AppendString(buf, reflectDepthStr)
+		return false
+	}
+	if ptr != 0 {
+		if st.isVisited(ptr) {
+			enc.AppendString(buf, reflectCycleStr)
+			return false
+		}
+		st.visited = append(st.visited, ptr)
+	}
+	st.depth++
+	return true
+}
+
+// isVisited returns true if the ptr is one of the ancestors of the current value.
+func (st *reflectState) isVisited(ptr uintptr) bool {
+	for _, p := range st.visited {
+		if p == ptr {
+			return true
+		}
+	}
+	return false
+}
+
+// leave decreases the depth after encoding a nested value, and removes the last visited ptr if recorded.
+func (st *reflectState) leave(recorded bool) {
+	st.depth--
+	if recorded {
+		st.visited = st.visited[:len(st.visited)-1]
+	}
+}
+
+// encodeStruct encodes the exported fields of the struct v as the key-value pairs of an object,
+// the fields of the embedded structs without a name in the json tag are promoted.
+// It returns the number of the encoded key-value pairs with the given n.
+func (st *reflectState) encodeStruct(enc ObjEncoder, buf *Buffer, v reflect.Value, n int) int {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		name, omitEmpty, ok := parseJsonTag(sf)
+		if !ok {
+			continue
+		}
+		fv := v.Field(i)
+		if sf.Anonymous && name == "" {
+			if sf.Type.Kind() == reflect.Struct {
+				n = st.encodeStruct(enc, buf, fv, n)
+				continue
+			}
+			if sf.Type.Kind() == reflect.Ptr && sf.Type.Elem().Kind() == reflect.Struct {
+				// Ignore the nil pointer and the pointer that refers to one of its ancestors silently,
+				// because the promoted fields have no key for a placeholder.
+				if fv.IsNil() || st.depth >= reflectMaxDepth || st.isVisited(fv.Pointer()) {
+					continue
+				}
+				st.visited = append(st.visited, fv.Pointer())
+				st.depth++
+				n = st.encodeStruct(enc, buf, fv.Elem(), n)
+				st.leave(true)
+				continue
+			}
+		}
+		if sf.PkgPath != "" {
+			// Ignore the unexported field.
+			continue
+		}
+		if omitEmpty && isEmptyValue(fv) {
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+		enc.AppendObjectKey(buf, name, n)
+		n++
+		st.encode(enc, buf, fv)
+	}
+	return n
+}
+
+// encodeMap encodes the map v as an object in the ascending order of its keys.
+func (st *reflectState) encodeMap(enc ObjEncoder, buf *Buffer, v reflect.Value) {
+	type entry struct {
+		key string
+		val reflect.Value
+	}
+	entries := make([]entry, 0, v.Len())
+	iter := v.MapRange()
+	for iter.Next() {
+		k := iter.Key()
+		key := ""
+		if k.Kind() == reflect.String {
+			key = k.String()
+		} else {
+			key = fmt.Sprint(k)
+		}
+		entries = append(entries, entry{key: key, val: iter.Value()})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].key < entries[j].key
+	})
+	enc.AppendObjectStart(buf)
+	for i, e := range entries {
+		enc.AppendObjectKey(buf, e.key, i)
+		st.encode(enc, buf, e.val)
+	}
+	enc.AppendObjectEnd(buf)
+}
+
+// reflectArray implements the ArrayEncoder interface for a slice or an array by reflection.
+type reflectArray struct {
+	st *reflectState
+	v  reflect.Value
+}
+
+func (a reflectArray) Size() int {
+	return a.v.Len()
+}
+
+func (a reflectArray) AppendEle(enc ObjEncoder, buf *Buffer, i int) {
+	a.st.encode(enc, buf, a.v.Index(i))
+}
+
+// parseJsonTag returns the name and the omitempty option in the json tag of the sf,
+// it returns false if the sf is ignored by the tag "-".
+func parseJsonTag(sf reflect.StructField) (name string, omitEmpty bool, ok bool) {
+	tag := sf.Tag.Get("json")
+	if tag == "-" {
+		return "", false, false
+	}
+	name = tag
+	if i := strings.IndexByte(tag, ','); i != -1 {
+		name = tag[:i]
+		for _, opt := range strings.Split(tag[i+1:], ",") {
+			if opt == "omitempty" {
+				omitEmpty = true
+			}
+		}
+	}
+	return name, omitEmpty, true
+}
+
+// isEmptyValue returns true if the v is empty in the same way as the omitempty option of encoding/json.
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Interface, reflect.Ptr:
+		return v.IsNil()
+	}
+	return false
+}
+
+// isReflectMarshaler returns true if the t implements any of the reflectMarshalerTypes.
+func isReflectMarshaler(t reflect.Type) bool {
+	for _, it := range reflectMarshalerTypes {
+		if t.Implements(it) {
+			return true
+		}
+	}
+	return false
+}
+
+// Reflect Returns a Field with the given key and value.
+// The val is encoded by reflection, the structs and maps are encoded as objects,
+// the slices and arrays are encoded as arrays except that the slices of bytes are encoded as strings,
+// the pointers are dereferenced, and the json tags of the struct fields are honored.
+// The pointers and maps that refer to their ancestors, the values that are nested too deep and
+// the values after the first 10000 values are replaced with the placeholders. If the given val is nil, then output "<nil>" when logging the val.
+func Reflect(key string, val interface{}) Field {
+	if val == nil {
+		return String(key, nilStr)
+	}
+	return Field{Key: key, Val: ReflectVal{V: val}}
+}
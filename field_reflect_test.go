@@ -0,0 +1,101 @@
+package wlog
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testBase struct {
+	ID int `json:"id"`
+}
+
+type testAccount struct {
+	testBase
+	Name     string           `json:"name"`
+	Email    string           `json:"email,omitempty"`
+	Password string           `json:"-"`
+	Tags     []string         `json:"tags"`
+	Attrs    map[string]int   `json:"attrs,omitempty"`
+	TTL      time.Duration    `json:"ttl"`
+	Err      error            `json:"err"`
+	Parent   *testAccount     `json:"parent,omitempty"`
+	Extra    map[int]testBase `json:"extra,omitempty"`
+	Any      interface{}      `json:"any"`
+	hidden   string
+}
+
+type testNode struct {
+	Name string
+	Next *testNode
+}
+
+func TestReflect(t *testing.T) {
+	acc := &testAccount{
+		testBase: testBase{ID: 1},
+		Name:     "root",
+		Password: "secret",
+		Tags:     []string{"a", "b"},
+		TTL:      time.Second,
+		Err:      errors.New("failed"),
+		Parent:   &testAccount{Name: "admin"},
+		Extra:    map[int]testBase{2: {ID: 2}},
+		Any:      []interface{}{1, "x"},
+		hidden:   "hidden",
+	}
+	je := NewJsonEncoder(SetLevelKey(""), DisableTime(), SetMsgKey(""))
+	out := encodeFields(je, Interface("acc", acc))
+	assert.Equal(t, `{"acc":{"id":1,"name":"root","tags":["a","b"],"ttl":"1s","err":{"msg":"failed","type":"*errors.errorString"},`+
+		`"parent":{"id":0,"name":"admin","tags":"<nil>","ttl":"0s","err":"<nil>","any":"<nil>"},`+
+		`"extra":{"2":{"id":2}},"any":[1,"x"]}}`+"\n", out)
+	assert.True(t, json.Valid([]byte(out)))
+
+	te := NewTextEncoder(SetLevelKey(""), DisableTime(), SetMsgKey(""))
+	assert.Equal(t, "[user={id=1 name=root tags=[] ttl=0s err=<nil> any=<nil>}]\n",
+		encodeFields(te, Reflect("user", testAccount{testBase: testBase{ID: 1}, Name: "root", Tags: []string{}})))
+
+	// The cycle is replaced with a placeholder.
+	node := &testNode{Name: "a"}
+	node.Next = &testNode{Name: "b", Next: node}
+	assert.Equal(t, `{"node":{"Name":"a","Next":{"Name":"b","Next":"<cycle>"}}}`+"\n", encodeFields(je, Reflect("node", node)))
+
+	// The map that contains itself is replaced with a placeholder.
+	m := map[string]interface{}{"name": "m"}
+	m["self"] = m
+	m["list"] = []interface{}{m}
+	expected := `{"m":{"list":["<cycle>"],"name":"m","self":"<cycle>"}}` + "\n"
+	assert.Equal(t, expected, encodeFields(je, Interface("m", m)))
+	assert.Equal(t, expected, encodeFields(je, Map("m", m)))
+	assert.Equal(t, expected, encodeFields(je, Reflect("m", m)))
+
+	// The value that is nested too deep is replaced with a placeholder.
+	var deep interface{} = "leaf"
+	for i := 0; i < reflectMaxDepth+1; i++ {
+		deep = []interface{}{deep}
+	}
+	out = encodeFields(je, Reflect("deep", deep))
+	assert.Contains(t, out, `["<max depth>"]`)
+	assert.True(t, json.Valid([]byte(out)))
+
+	// The values referred to by many paths are limited by the total number of values.
+	var shared interface{} = "leaf"
+	for i := 0; i < 14; i++ {
+		shared = []interface{}{shared, shared}
+	}
+	out = encodeFields(je, Reflect("shared", shared))
+	assert.True(t, strings.Count(out, `"leaf"`) < reflectMaxValues)
+	assert.Contains(t, out, `"<max values>"]`)
+	assert.True(t, json.Valid([]byte(out)))
+
+	// The bytes are encoded as a string, and the pointer to a scalar is dereferenced.
+	n := 5
+	assert.Equal(t, `{"bs":"hi","nested":{"B":"hi"},"n":5}`+"\n", encodeFields(je, Interface("bs", []byte("hi")),
+		Interface("nested", struct{ B []byte }{[]byte("hi")}), Interface("n", &n)))
+
+	assert.Equal(t, `{"nil":"<nil>","ids":[1,2],"point":{"X":1}}`+"\n",
+		encodeFields(je, Reflect("nil", nil), Interface("ids", [2]uint{1, 2}), Interface("point", struct{ X int }{1})))
+}
@@ -14,3 +14,30 @@ type Handler interface {
 	// Close closes the handler.
 	Close() error
 }
+
+// CheckHandler interface is implemented by the Handlers that may drop an entry instead of writing it,
+// such as the LevelHandler and SamplingHandler, and the Handlers that wrap them, so that a Logger
+// is able to know whether an entry would be written before constructing its fields.
+type CheckHandler interface {
+	// Enabled returns false if all entries at the lvl would be dropped.
+	Enabled(lvl Level) bool
+	// Check returns false if the entry would be dropped.
+	// It doesn't change the state of the Handler, such as the counts of the SamplingHandler.
+	Check(entry *Entry) bool
+}
+
+// handlerEnabled returns the result of the Enabled of h if h is a CheckHandler, otherwise true.
+func handlerEnabled(h Handler, lvl Level) bool {
+	if c, ok := h.(CheckHandler); ok {
+		return c.Enabled(lvl)
+	}
+	return true
+}
+
+// handlerCheck returns the result of the Check of h if h is a CheckHandler, otherwise true.
+func handlerCheck(h Handler, entry *Entry) bool {
+	if c, ok := h.(CheckHandler); ok {
+		return c.Check(entry)
+	}
+	return true
+}
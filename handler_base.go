@@ -18,6 +18,10 @@ func (h *BaseHandler) With(fields ...Field) Handler {
 }
 
 func (h *BaseHandler) Write(entry *Entry, fields ...Field) error {
+	// Marshal the lazy values, so that their errors can be reported as fields.
+	fields = marshalFields(fields)
+	// Add the fields carried by the logged errors, including the errors added by the With.
+	fields = expandErrorFields(fields)
 	buf := GetBuf()
 	err := h.encoder.Encode(buf, entry, fields...)
 	if err != nil {
@@ -0,0 +1,45 @@
+package wlog
+
+// LevelHandler is a Handler that only writes the entries whose levels are
+// between the minimum level and the maximum level to the inner Handler,
+// the others are dropped.
+type LevelHandler struct {
+	Handler
+	minLvl Level
+	maxLvl Level
+}
+
+// NewLevelHandler returns a LevelHandler that drops the entries below the given minLvl.
+func NewLevelHandler(inner Handler, minLvl Level) *LevelHandler {
+	return NewLevelRangeHandler(inner, minLvl, PanicLvl)
+}
+
+// NewLevelRangeHandler returns a LevelHandler that drops the entries
+// below the given minLvl or above the given maxLvl.
+func NewLevelRangeHandler(inner Handler, minLvl, maxLvl Level) *LevelHandler {
+	return &LevelHandler{
+		Handler: inner,
+		minLvl:  minLvl,
+		maxLvl:  maxLvl,
+	}
+}
+
+// Enabled returns true if the entry with the given lvl is allowed to be written.
+func (h *LevelHandler) Enabled(lvl Level) bool {
+	return lvl >= h.minLvl && lvl <= h.maxLvl && handlerEnabled(h.Handler, lvl)
+}
+
+func (h *LevelHandler) Check(entry *Entry) bool {
+	return h.Enabled(entry.Level) && handlerCheck(h.Handler, entry)
+}
+
+func (h *LevelHandler) With(fields ...Field) Handler {
+	return NewLevelRangeHandler(h.Handler.With(fields...), h.minLvl, h.maxLvl)
+}
+
+func (h *LevelHandler) Write(entry *Entry, fields ...Field) error {
+	if entry.Level < h.minLvl || entry.Level > h.maxLvl {
+		return nil
+	}
+	return h.Handler.Write(entry, fields...)
+}
@@ -0,0 +1,246 @@
+package wlog
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const samplingSummaryMsg = "SamplingHandler: some logs are dropped by sampling"
+
+// SamplingHandler is a Handler that samples the entries to cap the CPU and I/O load of logging.
+//
+// For every unique pair of level and message, it writes the first N entries in each tick,
+// and then writes every Mth entry thereafter, the others are dropped.
+// For example, with first = 100 and thereafter = 10, the entries from 1 to 100 are written,
+// and then the entries 110, 120, 130 and so on are written in each tick.
+// The entries at fatal-level and panic-level are never sampled.
+type SamplingHandler struct {
+	Handler
+	// s is shared by all SamplingHandlers derived from the same SamplingHandler.
+	s *sampler
+}
+
+type SamplingOpt func(h *SamplingHandler)
+
+// EnableSamplingSummary enables the SamplingHandler to write a summary entry at warn-level
+// with the number of dropped entries of every level when a tick that dropped any entries ends.
+// The summary is written by a timer even if no more entries are written after the tick,
+// and the error of writing it by the timer is output to os.Stderr.
+func EnableSamplingSummary() SamplingOpt {
+	return func(h *SamplingHandler) {
+		h.s.summaryEnabled = true
+	}
+}
+
+type samplingKey struct {
+	lvl Level
+	msg string
+}
+
+type sampler struct {
+	// root is the Handler to write the summary entries to.
+	root       Handler
+	tick       time.Duration
+	first      uint64
+	thereafter uint64
+
+	mu sync.Mutex
+	// counts records the number of entries of every key in the current tick.
+	counts map[samplingKey]uint64
+	// resetAt is the unix time in nanoseconds to reset the counts.
+	resetAt int64
+	// tickDropped is the number of dropped entries of every level in the current tick.
+	tickDropped [levelNum]uint64
+	// dropped is the total number of dropped entries of every level.
+	// It's updated and read atomically.
+	dropped [levelNum]uint64
+
+	summaryEnabled bool
+	// timer writes the summary at the end of the current tick if any entries are dropped in it.
+	timer *time.Timer
+	// timerWg waits for the summary being written by the timer.
+	timerWg sync.WaitGroup
+	closed  bool
+}
+
+// NewSamplingHandler returns a SamplingHandler that writes the first entries
+// and every thereafter-th entry thereafter in each tick for every unique pair of level and message.
+// If the given thereafter is 0, all entries after the first entries are dropped in each tick.
+func NewSamplingHandler(inner Handler, tick time.Duration, first, thereafter int, opts ...SamplingOpt) *SamplingHandler {
+	h := &SamplingHandler{
+		Handler: inner,
+		s: &sampler{
+			root:       inner,
+			tick:       tick,
+			first:      uint64(first),
+			thereafter: uint64(thereafter),
+			counts:     make(map[samplingKey]uint64),
+		},
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+// Dropped returns the total number of dropped entries at the given lvl.
+func (h *SamplingHandler) Dropped(lvl Level) uint64 {
+	return atomic.LoadUint64(&h.s.dropped[lvl])
+}
+
+func (h *SamplingHandler) With(fields ...Field) Handler {
+	return &SamplingHandler{Handler: h.Handler.With(fields...), s: h.s}
+}
+
+// Enabled returns the result of the inner Handler, because the SamplingHandler never drops all entries at a level.
+func (h *SamplingHandler) Enabled(lvl Level) bool {
+	return handlerEnabled(h.Handler, lvl)
+}
+
+// Check returns false if the entry would be dropped by sampling or the inner Handler.
+func (h *SamplingHandler) Check(entry *Entry) bool {
+	return h.s.check(entry) && handlerCheck(h.Handler, entry)
+}
+
+func (h *SamplingHandler) Write(entry *Entry, fields ...Field) error {
+	sampled, summary := h.s.sample(entry)
+	var err error
+	if summary != nil {
+		err = h.s.writeSummary(summary, entry.Time)
+	}
+	if sampled {
+		err = multiErr(err, h.Handler.Write(entry, fields...))
+	}
+	return err
+}
+
+// Close writes the summary of the current tick if necessary, and then closes the inner Handler.
+func (h *SamplingHandler) Close() error {
+	h.s.mu.Lock()
+	h.s.closed = true
+	h.s.stopTimer()
+	summary := h.s.takeSummary()
+	h.s.mu.Unlock()
+	// Wait for the summary being written by the timer before closing the inner Handler.
+	h.s.timerWg.Wait()
+	var err error
+	if summary != nil {
+		err = h.s.writeSummary(summary, time.Now())
+	}
+	return multiErr(err, h.Handler.Close())
+}
+
+// sample returns true if the entry should be written, and returns the dropped numbers
+// of the previous tick if the summary is required to be written.
+func (s *sampler) sample(entry *Entry) (bool, *[levelNum]uint64) {
+	if entry.Level >= FatalLvl {
+		return true, nil
+	}
+	now := entry.Time.UnixNano()
+	key := samplingKey{lvl: entry.Level, msg: entry.Msg}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var summary *[levelNum]uint64
+	if now >= s.resetAt {
+		s.stopTimer()
+		summary = s.takeSummary()
+		s.counts = make(map[samplingKey]uint64, len(s.counts))
+		s.resetAt = now + int64(s.tick)
+	}
+	n := s.counts[key] + 1
+	s.counts[key] = n
+	if s.allowed(n) {
+		return true, summary
+	}
+	s.tickDropped[entry.Level]++
+	atomic.AddUint64(&s.dropped[entry.Level], 1)
+	if s.summaryEnabled && s.timer == nil && !s.closed {
+		s.timerWg.Add(1)
+		var t *time.Timer
+		// The t is read by the flushSummary with s.mu held, so it's always assigned before.
+		t = time.AfterFunc(time.Duration(s.resetAt-now), func() { s.flushSummary(t) })
+		s.timer = t
+	}
+	return false, summary
+}
+
+// stopTimer stops the timer if it's not fired. It must be called with s.mu held.
+func (s *sampler) stopTimer() {
+	if s.timer != nil && s.timer.Stop() {
+		s.timerWg.Done()
+	}
+	s.timer = nil
+}
+
+// flushSummary writes the summary of the current tick when the timer t fires.
+func (s *sampler) flushSummary(t *time.Timer) {
+	defer s.timerWg.Done()
+	s.mu.Lock()
+	if s.timer == t {
+		s.timer = nil
+	}
+	var summary *[levelNum]uint64
+	if !s.closed {
+		summary = s.takeSummary()
+	}
+	s.mu.Unlock()
+	if summary == nil {
+		return
+	}
+	if err := s.writeSummary(summary, time.Now()); err != nil {
+		t := time.Now().Format("2006-01-02 15:04:05")
+		fmt.Fprintf(os.Stderr, "SamplingHandler: unable to write the summary at time: %v, error: %v\n", t, err)
+	}
+}
+
+// check returns true if the entry would be written by the sample without changing the counts.
+func (s *sampler) check(entry *Entry) bool {
+	if entry.Level >= FatalLvl {
+		return true
+	}
+	key := samplingKey{lvl: entry.Level, msg: entry.Msg}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if entry.Time.UnixNano() >= s.resetAt {
+		return true
+	}
+	n := s.counts[key] + 1
+	return s.allowed(n)
+}
+
+// allowed returns true if the n-th entry of a key in a tick should be written.
+func (s *sampler) allowed(n uint64) bool {
+	return n <= s.first || (s.thereafter > 0 && (n-s.first)%s.thereafter == 0)
+}
+
+// takeSummary returns the dropped numbers of the current tick and resets them,
+// it returns nil if the summary is disabled or no entries are dropped.
+// It must be called with s.mu held.
+func (s *sampler) takeSummary() *[levelNum]uint64 {
+	if !s.summaryEnabled || s.tickDropped == [levelNum]uint64{} {
+		return nil
+	}
+	summary := s.tickDropped
+	s.tickDropped = [levelNum]uint64{}
+	return &summary
+}
+
+// writeSummary writes a summary entry with the dropped numbers of every level to the root Handler.
+func (s *sampler) writeSummary(summary *[levelNum]uint64, t time.Time) error {
+	fields := make([]Field, 0, levelNum+1)
+	fields = append(fields, Duration("tick", s.tick))
+	for i, n := range summary {
+		if n > 0 {
+			fields = append(fields, Uint64(levels[i].LowerStr(), n))
+		}
+	}
+	e := getEntry()
+	e.Set(WarnLvl, samplingSummaryMsg)
+	e.Time = t
+	err := s.root.Write(e, fields...)
+	putEntry(e)
+	return err
+}
@@ -0,0 +1,66 @@
+package wlog
+
+// TeeHandler writes every entry and fields to all underlying Handlers,
+// so that the same log can be encoded by different Encoders and
+// written to different Writers at the same time.
+type TeeHandler struct {
+	hs []Handler
+}
+
+func NewTeeHandler(hs ...Handler) *TeeHandler {
+	return &TeeHandler{hs: hs}
+}
+
+func (h *TeeHandler) With(fields ...Field) Handler {
+	hs := make([]Handler, len(h.hs))
+	for i, inner := range h.hs {
+		hs[i] = inner.With(fields...)
+	}
+	return NewTeeHandler(hs...)
+}
+
+// Enabled returns true if any underlying Handler is enabled at the lvl.
+func (h *TeeHandler) Enabled(lvl Level) bool {
+	for _, inner := range h.hs {
+		if handlerEnabled(inner, lvl) {
+			return true
+		}
+	}
+	return false
+}
+
+// Check returns true if any underlying Handler would write the entry.
+func (h *TeeHandler) Check(entry *Entry) bool {
+	for _, inner := range h.hs {
+		if handlerCheck(inner, entry) {
+			return true
+		}
+	}
+	return false
+}
+
+// Write writes the entry and fields to every underlying Handler,
+// an error of any Handler does not prevent the others from writing.
+func (h *TeeHandler) Write(entry *Entry, fields ...Field) error {
+	var err error
+	for _, inner := range h.hs {
+		err = multiErr(err, inner.Write(entry, fields...))
+	}
+	return err
+}
+
+func (h *TeeHandler) Flush() error {
+	var err error
+	for _, inner := range h.hs {
+		err = multiErr(err, inner.Flush())
+	}
+	return err
+}
+
+func (h *TeeHandler) Close() error {
+	var err error
+	for _, inner := range h.hs {
+		err = multiErr(err, inner.Close())
+	}
+	return err
+}
@@ -0,0 +1,206 @@
+package wlog
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestHandler(buf *bytes.Buffer) Handler {
+	return NewBaseHandler(NewIOWriter(buf), NewTextEncoder(DisableTime()))
+}
+
+func TestWithHandler(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := NewLogger(newTestHandler(buf)).With(String("name", "xcj"))
+	logger.With(Int("age", 10)).Infow("child")
+	logger.Infow("parent")
+	assert.Equal(t, "[INFO]  child  [name=xcj age=10]\n[INFO]  parent  [name=xcj]\n", buf.String())
+}
+
+func TestLevelHandler(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := NewLogger(NewLevelHandler(newTestHandler(buf), WarnLvl))
+	logger.Infow("dropped")
+	logger.With(String("name", "xcj")).Warnw("written")
+	assert.Equal(t, "[WARN]  written  [name=xcj]\n", buf.String())
+
+	buf.Reset()
+	logger = NewLogger(NewLevelRangeHandler(newTestHandler(buf), InfoLvl, WarnLvl))
+	logger.Debugw("dropped")
+	logger.Infow("written")
+	logger.Errorw("dropped")
+	assert.Equal(t, "[INFO]  written\n", buf.String())
+}
+
+func TestConfigPathMinLevels(t *testing.T) {
+	c := Config{Paths: []string{"stdout", "stderr"}, PathMinLevels: map[string]string{"stderr": "error"}}
+	h, err := c.CreateHandler(nil)
+	assert.NoError(t, err)
+	hs := h.(*TeeHandler).hs
+	assert.IsType(t, &BaseHandler{}, hs[0])
+	assert.Equal(t, NewLevelHandler(hs[1].(*LevelHandler).Handler, ErrorLvl), hs[1])
+	assert.NoError(t, h.Close())
+
+	c.PathMinLevels = map[string]string{"stderr": "verbose"}
+	_, err = c.CreateHandler(nil)
+	assert.Error(t, err)
+	c.PathMinLevels = map[string]string{"tmp.log": "error"}
+	_, err = c.CreateHandler(nil)
+	assert.Error(t, err)
+}
+
+type errHandler struct {
+	Handler
+	err error
+}
+
+func (h errHandler) Write(entry *Entry, fields ...Field) error {
+	return h.err
+}
+
+func TestTeeHandler(t *testing.T) {
+	textBuf, jsonBuf := &bytes.Buffer{}, &bytes.Buffer{}
+	jsonHandler := NewBaseHandler(NewIOWriter(jsonBuf), NewJsonEncoder(DisableTime()))
+	h := NewTeeHandler(newTestHandler(textBuf), NewLevelHandler(jsonHandler, ErrorLvl))
+	logger := NewLogger(h).With(String("name", "xcj"))
+	logger.Infow("info")
+	logger.Errorw("error")
+	assert.Equal(t, "[INFO]  info  [name=xcj]\n[ERROR]  error  [name=xcj]\n", textBuf.String())
+	assert.Equal(t, `{"level":"error","msg":"error","name":"xcj"}`+"\n", jsonBuf.String())
+	assert.NoError(t, logger.Close())
+
+	h = NewTeeHandler(errHandler{err: errors.New("err1")}, newTestHandler(textBuf), errHandler{err: errors.New("err2")})
+	assert.EqualError(t, h.Write(&Entry{}), "err1;err2")
+}
+
+func TestConfigPathEncoders(t *testing.T) {
+	c := Config{Paths: []string{"stdout", "stderr"},
+		PathEncoders: map[string]PathEncoderConfig{"stderr": {Encoder: "json"}}}
+	h, err := c.CreateHandler(nil)
+	assert.NoError(t, err)
+	hs := h.(*TeeHandler).hs
+	assert.IsType(t, &TextEncoder{}, hs[0].(*BaseHandler).encoder)
+	assert.IsType(t, &JsonEncoder{}, hs[1].(*BaseHandler).encoder)
+	assert.NoError(t, h.Close())
+}
+
+type countHook struct {
+	lvls  []Level
+	count int64
+	err   error
+}
+
+func (h *countHook) Levels() []Level {
+	return h.lvls
+}
+
+func (h *countHook) Fire(entry *Entry, fields []Field) error {
+	atomic.AddInt64(&h.count, 1)
+	return h.err
+}
+
+// syncBuffer is a bytes.Buffer that is safe for concurrent writes.
+type syncBuffer struct {
+	mu sync.Mutex
+	bytes.Buffer
+}
+
+func (b *syncBuffer) Write(bs []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.Buffer.Write(bs)
+}
+
+func TestHookHandler(t *testing.T) {
+	buf, errBuf := &syncBuffer{}, &syncBuffer{}
+	errHook := &countHook{lvls: []Level{ErrorLvl}}
+	alarmHook := &countHook{lvls: []Level{ErrorLvl, FatalLvl}, err: errors.New("alarm failed")}
+	h := NewBaseHandler(NewIOWriter(buf), NewTextEncoder(DisableTime()))
+	logger := NewLogger(h, SetLogErrW(errBuf), AddLogHooks(errHook, alarmHook))
+	var wg sync.WaitGroup
+	wg.Add(5)
+	for g := 0; g < 5; g++ {
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 10; i++ {
+				logger.With(String("name", "xcj")).Errorw("test logger")
+				logger.Infow("test logger")
+			}
+		}()
+	}
+	wg.Wait()
+	assert.EqualValues(t, 50, atomic.LoadInt64(&errHook.count))
+	assert.EqualValues(t, 50, atomic.LoadInt64(&alarmHook.count))
+	assert.Equal(t, 50, bytes.Count(errBuf.Bytes(), []byte("hook: alarm failed")))
+	assert.Equal(t, 50, bytes.Count(buf.Bytes(), []byte("[ERROR]  test logger  [name=xcj]")))
+
+	// The hooks are not fired for the entries dropped by the inner Handler,
+	// and the invalid levels of a hook are ignored.
+	infoHook := &countHook{lvls: []Level{InfoLvl, levelNum, 255}}
+	buf.Reset()
+	sh := NewSamplingHandler(NewLevelHandler(newTestHandler(&buf.Buffer), InfoLvl), time.Hour, 1, 0)
+	logger = NewLogger(sh, AddLogHooks(infoHook))
+	for i := 0; i < 5; i++ {
+		logger.Infow("sampled")
+		logger.Debugw("dropped")
+	}
+	assert.EqualValues(t, 1, atomic.LoadInt64(&infoHook.count))
+	assert.Equal(t, "[INFO]  sampled\n", buf.String())
+}
+
+func TestSamplingHandler(t *testing.T) {
+	buf := &bytes.Buffer{}
+	h := NewSamplingHandler(newTestHandler(buf), time.Hour, 2, 3, EnableSamplingSummary())
+	logger := NewLogger(h).With(String("name", "xcj"))
+	for i := 0; i < 10; i++ {
+		logger.Infow("sampled")
+	}
+	logger.Warnw("other")
+	assert.Equal(t, 4, strings.Count(buf.String(), "[INFO]  sampled  [name=xcj]"))
+	assert.Equal(t, 1, strings.Count(buf.String(), "[WARN]  other"))
+	assert.EqualValues(t, 6, h.Dropped(InfoLvl))
+	assert.EqualValues(t, 0, h.Dropped(WarnLvl))
+
+	buf.Reset()
+	assert.NoError(t, h.Close())
+	assert.Equal(t, "[WARN]  "+samplingSummaryMsg+"  [tick=1h0m0s info=6]\n", buf.String())
+}
+
+func TestSamplingHandlerTick(t *testing.T) {
+	buf := &bytes.Buffer{}
+	h := NewSamplingHandler(newTestHandler(buf), time.Minute, 1, 0, EnableSamplingSummary())
+	e := &Entry{Level: InfoLvl, Time: time.Now(), Msg: "sampled"}
+	assert.NoError(t, h.Write(e))
+	assert.NoError(t, h.Write(e))
+	e.Time = e.Time.Add(time.Minute)
+	assert.NoError(t, h.Write(e))
+	assert.Equal(t, "[INFO]  sampled\n[WARN]  "+samplingSummaryMsg+"  [tick=1m0s info=1]\n[INFO]  sampled\n", buf.String())
+}
+
+func TestSamplingHandlerSummaryTimer(t *testing.T) {
+	buf := &syncBuffer{}
+	inner := NewBaseHandler(NewIOWriter(buf), NewTextEncoder(DisableTime()))
+	h := NewSamplingHandler(inner, 20*time.Millisecond, 1, 0, EnableSamplingSummary())
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, h.Write(&Entry{Level: InfoLvl, Time: time.Now(), Msg: "sampled"}))
+		// The entries at fatal-level are never sampled.
+		assert.NoError(t, h.Write(&Entry{Level: FatalLvl, Time: time.Now(), Msg: "never sampled"}))
+	}
+	// The summary is written at the end of the tick even if no more entries are written.
+	expected := "[INFO]  sampled\n" + strings.Repeat("[FATAL]  never sampled\n", 3) +
+		"[WARN]  " + samplingSummaryMsg + "  [tick=20ms info=2]\n"
+	assert.Eventually(t, func() bool {
+		buf.mu.Lock()
+		defer buf.mu.Unlock()
+		return buf.String() == expected
+	}, time.Second, 5*time.Millisecond)
+	assert.NoError(t, h.Close())
+	assert.EqualValues(t, 0, h.Dropped(FatalLvl))
+}
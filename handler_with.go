@@ -7,14 +7,28 @@ type WithHandler struct {
 
 func NewWithHandler(inner Handler) *WithHandler {
 	return &WithHandler{
-		Handler:      inner,
-		fields: make([]Field, 0, 2),
+		Handler: inner,
+		fields:  make([]Field, 0, 2),
 	}
 }
 
+// With returns a new WithHandler with the fields of h and the given fields,
+// h itself is never modified, so it's safe to share h by multiple Loggers.
 func (h *WithHandler) With(fields ...Field) Handler {
-	h.fields = append(h.fields, fields...)
-	return h
+	// The capacity of the clone's fields is exactly enough, so that appending any fields
+	// to it when writing an entry always allocates a new slice.
+	all := make([]Field, 0, len(h.fields)+len(fields))
+	all = append(all, h.fields...)
+	all = append(all, fields...)
+	return &WithHandler{Handler: h.Handler, fields: all}
+}
+
+func (h *WithHandler) Enabled(lvl Level) bool {
+	return handlerEnabled(h.Handler, lvl)
+}
+
+func (h *WithHandler) Check(entry *Entry) bool {
+	return handlerCheck(h.Handler, entry)
 }
 
 func (h *WithHandler) Write(entry *Entry, fields ...Field) error {
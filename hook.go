@@ -0,0 +1,87 @@
+package wlog
+
+import "fmt"
+
+// Hook is fired after the entry at the specified levels is written successfully.
+// It's usually used to count the logs, send the alarms and etc.
+//
+// Note that a Hook may be fired concurrently, so it must be safe for concurrent use.
+type Hook interface {
+	// Levels returns all levels the Hook cares about, the invalid levels are ignored.
+	Levels() []Level
+	// Fire is called with the entry and all fields after they are written successfully.
+	// The entry and fields must not be retained after returning.
+	Fire(entry *Entry, fields []Field) error
+}
+
+// AddLogHooks wraps the underlying Handler of the Logger into a HookHandler with the given hooks.
+func AddLogHooks(hooks ...Hook) LoggerOpt {
+	return WrapLogHandler(func(h Handler) Handler {
+		return NewHookHandler(h, hooks...)
+	})
+}
+
+// HookHandler fires all hooks registered for the level of an entry
+// after the inner Handler writes the entry successfully.
+type HookHandler struct {
+	Handler
+	// hooks contains all hooks grouped by level, it's never modified after creation.
+	hooks *[levelNum][]Hook
+	// fields are the fields added by With, they are passed to both the inner Handler and hooks.
+	fields []Field
+}
+
+func NewHookHandler(inner Handler, hooks ...Hook) *HookHandler {
+	var grouped [levelNum][]Hook
+	for _, hook := range hooks {
+		for _, lvl := range hook.Levels() {
+			if lvl >= levelNum {
+				continue
+			}
+			grouped[lvl] = append(grouped[lvl], hook)
+		}
+	}
+	return &HookHandler{Handler: inner, hooks: &grouped}
+}
+
+func (h *HookHandler) With(fields ...Field) Handler {
+	all := make([]Field, 0, len(h.fields)+len(fields))
+	all = append(all, h.fields...)
+	all = append(all, fields...)
+	return &HookHandler{Handler: h.Handler, hooks: h.hooks, fields: all}
+}
+
+func (h *HookHandler) Enabled(lvl Level) bool {
+	return handlerEnabled(h.Handler, lvl)
+}
+
+func (h *HookHandler) Check(entry *Entry) bool {
+	return handlerCheck(h.Handler, entry)
+}
+
+// Write writes the entry and fields to the inner Handler, and then fires the hooks
+// if the entry is written successfully. The hooks are not fired if the entry is dropped
+// by the inner Handler, such as a LevelHandler or a SamplingHandler, which is determined by
+// the Check of the inner Handler before writing. All errors returned by the hooks are combined
+// into one error prefixed with "hook: ", so that it's distinguished from the error of writing.
+func (h *HookHandler) Write(entry *Entry, fields ...Field) error {
+	if len(h.fields) > 0 {
+		fields = append(h.fields, fields...)
+	}
+	hooks := h.hooks[entry.Level]
+	if len(hooks) > 0 && !handlerCheck(h.Handler, entry) {
+		// The entry is dropped by the inner Handler, so there is nothing to fire.
+		hooks = nil
+	}
+	if err := h.Handler.Write(entry, fields...); err != nil {
+		return err
+	}
+	var err error
+	for _, hook := range hooks {
+		err = multiErr(err, hook.Fire(entry, fields))
+	}
+	if err != nil {
+		return fmt.Errorf("hook: %w", err)
+	}
+	return nil
+}
@@ -1,6 +1,7 @@
 package wlog
 
 import (
+	"fmt"
 	"strings"
 )
 
@@ -88,3 +89,48 @@ func (l Level) ColorfulStr(isLower bool) string {
 	}
 	return l.UpperColorfulStr()
 }
+
+// ParseLevel returns the log level corresponding to the given case-insensitive str.
+// The supported values are as follow: "debug", "info", "warn", "warning", "error", "fatal", "panic".
+func ParseLevel(str string) (Level, error) {
+	lower := strings.ToLower(str)
+	if lower == "warning" {
+		return WarnLvl, nil
+	}
+	lvl, ok := lookupLevel(lower)
+	if !ok {
+		return DebugLvl, fmt.Errorf("unrecognized log level: %q", str)
+	}
+	return lvl, nil
+}
+
+// MarshalText marshals the log level to the lowercase text.
+// It implements the encoding.TextMarshaler interface.
+func (l Level) MarshalText() ([]byte, error) {
+	if l >= levelNum {
+		return nil, fmt.Errorf("invalid log level: %d", l)
+	}
+	return []byte(l.LowerStr()), nil
+}
+
+// UnmarshalText unmarshals the case-insensitive text to the log level.
+// It implements the encoding.TextUnmarshaler interface.
+func (l *Level) UnmarshalText(text []byte) error {
+	lvl, err := ParseLevel(string(text))
+	if err != nil {
+		return err
+	}
+	*l = lvl
+	return nil
+}
+
+// lookupLevel returns the log level corresponding to the given lowercase str,
+// such as "debug", "info", "warn", "error", "fatal" and "panic".
+func lookupLevel(str string) (Level, bool) {
+	for _, lvl := range levels {
+		if lvl.LowerStr() == str {
+			return lvl, true
+		}
+	}
+	return DebugLvl, false
+}
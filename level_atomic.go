@@ -0,0 +1,31 @@
+package wlog
+
+import "sync/atomic"
+
+// AtomicLevel is a log level that can be safely changed at runtime.
+// All copies of an AtomicLevel share the same underlying level,
+// so it can be shared by multiple Loggers to change their levels at the same time.
+type AtomicLevel struct {
+	lvl *uint32
+}
+
+// NewAtomicLevel returns a AtomicLevel with the given initial lvl.
+func NewAtomicLevel(lvl Level) AtomicLevel {
+	v := uint32(lvl)
+	return AtomicLevel{lvl: &v}
+}
+
+// Level returns the current log level.
+func (a AtomicLevel) Level() Level {
+	return Level(atomic.LoadUint32(a.lvl))
+}
+
+// Set changes the current log level to the given lvl.
+func (a AtomicLevel) Set(lvl Level) {
+	atomic.StoreUint32(a.lvl, uint32(lvl))
+}
+
+// Enabled returns true if the given lvl is at or above the current log level.
+func (a AtomicLevel) Enabled(lvl Level) bool {
+	return lvl >= a.Level()
+}
@@ -0,0 +1,69 @@
+package wlog
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+)
+
+type levelPayload struct {
+	Level string `json:"level"`
+}
+
+type errorPayload struct {
+	Error string `json:"error"`
+}
+
+// ServeHTTP is a simple JSON endpoint that reports or changes the current log level.
+//
+// GET requests return a JSON description of the current log level, such as:
+//
+//	{"level":"info"}
+//
+// PUT requests change the current log level, the new level is specified either
+// by a JSON body such as {"level":"debug"} or by a form value named "level".
+// The supported levels are the same as the Config's MinLevel.
+func (a AtomicLevel) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		writeLevelPayload(w, http.StatusOK, levelPayload{Level: a.Level().LowerStr()})
+	case http.MethodPut:
+		lvl, err := decodeLevelRequest(r)
+		if err != nil {
+			writeLevelPayload(w, http.StatusBadRequest, errorPayload{Error: err.Error()})
+			return
+		}
+		a.Set(lvl)
+		writeLevelPayload(w, http.StatusOK, levelPayload{Level: lvl.LowerStr()})
+	default:
+		msg := fmt.Sprintf("method %v is not allowed, only GET and PUT are supported", r.Method)
+		writeLevelPayload(w, http.StatusMethodNotAllowed, errorPayload{Error: msg})
+	}
+}
+
+// decodeLevelRequest returns the log level specified by the request r.
+func decodeLevelRequest(r *http.Request) (Level, error) {
+	var str string
+	// The media type may have parameters, such as "application/x-www-form-urlencoded; charset=utf-8".
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType == "application/x-www-form-urlencoded" {
+		str = r.FormValue("level")
+	} else {
+		var p levelPayload
+		if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+			return DebugLvl, fmt.Errorf("request body must be well-formed JSON: %v", err)
+		}
+		str = p.Level
+	}
+	if str == "" {
+		return DebugLvl, fmt.Errorf("must specify a log level")
+	}
+	return ParseLevel(str)
+}
+
+func writeLevelPayload(w http.ResponseWriter, code int, payload interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(payload)
+}
@@ -0,0 +1,56 @@
+package wlog
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAtomicLevelServeHTTP(t *testing.T) {
+	lvl := NewAtomicLevel(InfoLvl)
+	srv := httptest.NewServer(lvl)
+	defer srv.Close()
+
+	tests := []struct {
+		method      string
+		contentType string
+		body        string
+		code        int
+		resp        string
+		expected    Level
+	}{
+		{http.MethodGet, "", "", http.StatusOK, `{"level":"info"}`, InfoLvl},
+		{http.MethodPut, "application/json", `{"level":"debug"}`, http.StatusOK, `{"level":"debug"}`, DebugLvl},
+		{http.MethodPut, "application/x-www-form-urlencoded", url.Values{"level": {"error"}}.Encode(),
+			http.StatusOK, `{"level":"error"}`, ErrorLvl},
+		{http.MethodPut, "application/x-www-form-urlencoded; charset=utf-8", url.Values{"level": {"warn"}}.Encode(),
+			http.StatusOK, `{"level":"warn"}`, WarnLvl},
+		{http.MethodPut, "application/json", `{"level":"verbose"}`, http.StatusBadRequest,
+			`{"error":"unrecognized log level: \"verbose\""}`, WarnLvl},
+		{http.MethodPut, "application/json", `{}`, http.StatusBadRequest, `{"error":"must specify a log level"}`, WarnLvl},
+		{http.MethodPut, "application/json", `level`, http.StatusBadRequest, "", WarnLvl},
+		{http.MethodPost, "", "", http.StatusMethodNotAllowed, "", WarnLvl},
+	}
+	for _, tt := range tests {
+		req, err := http.NewRequest(tt.method, srv.URL, strings.NewReader(tt.body))
+		assert.NoError(t, err)
+		if tt.contentType != "" {
+			req.Header.Set("Content-Type", tt.contentType)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		assert.NoError(t, err)
+		assert.Equal(t, tt.code, resp.StatusCode)
+		if tt.resp != "" {
+			body, err := ioutil.ReadAll(resp.Body)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.resp+"\n", string(body))
+		}
+		resp.Body.Close()
+		assert.Equal(t, tt.expected, lvl.Level())
+	}
+}
@@ -0,0 +1,54 @@
+package wlog
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseLevel(t *testing.T) {
+	tests := []struct {
+		str      string
+		expected Level
+	}{
+		{"debug", DebugLvl},
+		{"INFO", InfoLvl},
+		{"Warn", WarnLvl},
+		{"warning", WarnLvl},
+		{"error", ErrorLvl},
+		{"fatal", FatalLvl},
+		{"panic", PanicLvl},
+	}
+	for _, tt := range tests {
+		lvl, err := ParseLevel(tt.str)
+		assert.NoError(t, err)
+		assert.Equal(t, tt.expected, lvl)
+	}
+	_, err := ParseLevel("verbose")
+	assert.EqualError(t, err, `unrecognized log level: "verbose"`)
+}
+
+func TestLevelText(t *testing.T) {
+	for _, lvl := range levels {
+		text, err := lvl.MarshalText()
+		assert.NoError(t, err)
+		var unmarshaled Level
+		assert.NoError(t, unmarshaled.UnmarshalText(text))
+		assert.Equal(t, lvl, unmarshaled)
+	}
+	_, err := Level(levelNum).MarshalText()
+	assert.Error(t, err)
+	var lvl Level
+	assert.Error(t, lvl.UnmarshalText([]byte("verbose")))
+}
+
+func TestConfigCreateLevel(t *testing.T) {
+	lvl, err := Config{}.CreateLevel()
+	assert.NoError(t, err)
+	assert.Equal(t, DebugLvl, lvl)
+	lvl, err = Config{MinLevel: "error"}.CreateLevel()
+	assert.NoError(t, err)
+	assert.Equal(t, ErrorLvl, lvl)
+	_, err = Config{MinLevel: "verbose"}.Create()
+	assert.Error(t, err)
+}
@@ -1,22 +1,29 @@
 package wlog
 
-import "fmt"
+import (
+	"context"
+	"fmt"
+)
 
 // globalLogger is the logger that can be conveniently used in all packages.
 var globalLogger *Logger
 
+// globalHelperLogger is the globalLogger used by the package-level logging functions,
+// it skips the additional stack frame of these functions when recording the caller.
+var globalHelperLogger *Logger
+
 // UseGlobalLogger initializes the global logger, if the given logger is nil
 // use method NewConsoleConfig().Create(...Option) to initialize it.
 func UseGlobalLogger(logger *Logger) {
-	if logger != nil {
-		globalLogger = logger
-		return
-	}
-	var err error
-	globalLogger, err = NewConsoleConfig().Create()
-	if err != nil {
-		panic(fmt.Sprint("failed to initialize the global logger, error: ", err.Error()))
+	if logger == nil {
+		var err error
+		logger, err = NewConsoleConfig().Create()
+		if err != nil {
+			panic(fmt.Sprint("failed to initialize the global logger, error: ", err.Error()))
+		}
 	}
+	globalLogger = logger
+	globalHelperLogger = logger.WithOpts(AddLogCallerSkip(1))
 }
 
 // WithOpts is the WithOpts method of a Logger that can be conveniently used in all packages.
@@ -34,124 +41,164 @@ func Withp(pairs ...interface{}) *Logger {
 	return globalLogger.Withp(pairs...)
 }
 
+// Enabled is the Enabled method of a Logger that can be conveniently used in all packages.
+func Enabled(lvl Level) bool {
+	return globalLogger.Enabled(lvl)
+}
+
+// Check is the Check method of a Logger that can be conveniently used in all packages.
+func Check(lvl Level, msg string) *CheckedEntry {
+	return globalLogger.Check(lvl, msg)
+}
+
 // Debug is the Debug method of a Logger that can be conveniently used in all packages.
 func Debug(args ...interface{}) {
-	globalLogger.Debug(args...)
+	globalHelperLogger.Debug(args...)
 }
 
 // Info is the Info method of a Logger that can be conveniently used in all packages.
 func Info(args ...interface{}) {
-	globalLogger.Info(args...)
+	globalHelperLogger.Info(args...)
 }
 
 // WithOpts is the WithOpts method of a Logger that can be conveniently used in all packages.
 func Warn(args ...interface{}) {
-	globalLogger.Warn(args...)
+	globalHelperLogger.Warn(args...)
 }
 
 // Error is the Error method of a Logger that can be conveniently used in all packages.
 func Error(args ...interface{}) {
-	globalLogger.Error(args...)
+	globalHelperLogger.Error(args...)
 }
 
 // Fatal is the Fatal method of a Logger that can be conveniently used in all packages.
 func Fatal(args ...interface{}) {
-	globalLogger.Fatal(args...)
+	globalHelperLogger.Fatal(args...)
 }
 
 // Panic is the Panic method of a Logger that can be conveniently used in all packages.
 func Panic(args ...interface{}) {
-	globalLogger.Panic(args...)
+	globalHelperLogger.Panic(args...)
 }
 
 // Debugf is the Debugf method of a Logger that can be conveniently used in all packages.
 func Debugf(format string, args ...interface{}) {
-	globalLogger.Debugf(format, args...)
+	globalHelperLogger.Debugf(format, args...)
 }
 
 // Infof is the Infof method of a Logger that can be conveniently used in all packages.
 func Infof(format string, args ...interface{}) {
-	globalLogger.Infof(format, args...)
+	globalHelperLogger.Infof(format, args...)
 }
 
 // Warnf is the Warnf method of a Logger that can be conveniently used in all packages.
 func Warnf(format string, args ...interface{}) {
-	globalLogger.Warnf(format, args...)
+	globalHelperLogger.Warnf(format, args...)
 }
 
 // Errorf is the Errorf method of a Logger that can be conveniently used in all packages.
 func Errorf(format string, args ...interface{}) {
-	globalLogger.Errorf(format, args...)
+	globalHelperLogger.Errorf(format, args...)
 }
 
 // Fatalf is the Fatalf method of a Logger that can be conveniently used in all packages.
 func Fatalf(format string, args ...interface{}) {
-	globalLogger.Fatalf(format, args...)
+	globalHelperLogger.Fatalf(format, args...)
 }
 
 // Panicf is the Panicf method of a Logger that can be conveniently used in all packages.
 func Panicf(format string, args ...interface{}) {
-	globalLogger.Panicf(format, args...)
+	globalHelperLogger.Panicf(format, args...)
 }
 
 // Debugw is the Debugw method of a Logger that can be conveniently used in all packages.
 func Debugw(msg string, fields ...Field) {
-	globalLogger.Debugw(msg, fields...)
+	globalHelperLogger.Debugw(msg, fields...)
 }
 
 // Infow is the Infow method of a Logger that can be conveniently used in all packages.
 func Infow(msg string, fields ...Field) {
-	globalLogger.Infow(msg, fields...)
+	globalHelperLogger.Infow(msg, fields...)
 }
 
 // Warnw is the Warnw method of a Logger that can be conveniently used in all packages.
 func Warnw(msg string, fields ...Field) {
-	globalLogger.Warnw(msg, fields...)
+	globalHelperLogger.Warnw(msg, fields...)
 }
 
 // Errorw is the Errorw method of a Logger that can be conveniently used in all packages.
 func Errorw(msg string, fields ...Field) {
-	globalLogger.Errorw(msg, fields...)
+	globalHelperLogger.Errorw(msg, fields...)
 }
 
 // Fatalw is the Fatalw method of a Logger that can be conveniently used in all packages.
 func Fatalw(msg string, fields ...Field) {
-	globalLogger.Fatalw(msg, fields...)
+	globalHelperLogger.Fatalw(msg, fields...)
 }
 
 // Panicw is the Panicw method of a Logger that can be conveniently used in all packages.
 func Panicw(msg string, fields ...Field) {
-	globalLogger.Panicw(msg, fields...)
+	globalHelperLogger.Panicw(msg, fields...)
 }
 
 // Debugp is the Debugp method of a Logger that can be conveniently used in all packages.
 func Debugp(msg string, pairs ...interface{}) {
-	globalLogger.Debugp(msg, pairs...)
+	globalHelperLogger.Debugp(msg, pairs...)
 }
 
 // Infop is the Infop method of a Logger that can be conveniently used in all packages.
 func Infop(msg string, pairs ...interface{}) {
-	globalLogger.Infop(msg, pairs...)
+	globalHelperLogger.Infop(msg, pairs...)
 }
 
 // Warnp is the Warnp method of a Logger that can be conveniently used in all packages.
 func Warnp(msg string, pairs ...interface{}) {
-	globalLogger.Warnp(msg, pairs...)
+	globalHelperLogger.Warnp(msg, pairs...)
 }
 
 // Errorp is the Errorp method of a Logger that can be conveniently used in all packages.
 func Errorp(msg string, pairs ...interface{}) {
-	globalLogger.Errorp(msg, pairs...)
+	globalHelperLogger.Errorp(msg, pairs...)
 }
 
 // Fatalp is the Fatalp method of a Logger that can be conveniently used in all packages.
 func Fatalp(msg string, pairs ...interface{}) {
-	globalLogger.Fatalp(msg, pairs...)
+	globalHelperLogger.Fatalp(msg, pairs...)
 }
 
 // Panicp is the Panicp method of a Logger that can be conveniently used in all packages.
 func Panicp(msg string, pairs ...interface{}) {
-	globalLogger.Panicp(msg, pairs...)
+	globalHelperLogger.Panicp(msg, pairs...)
+}
+
+// DebugCtx is the DebugCtx method of a Logger that can be conveniently used in all packages.
+func DebugCtx(ctx context.Context, msg string, fields ...Field) {
+	globalHelperLogger.DebugCtx(ctx, msg, fields...)
+}
+
+// InfoCtx is the InfoCtx method of a Logger that can be conveniently used in all packages.
+func InfoCtx(ctx context.Context, msg string, fields ...Field) {
+	globalHelperLogger.InfoCtx(ctx, msg, fields...)
+}
+
+// WarnCtx is the WarnCtx method of a Logger that can be conveniently used in all packages.
+func WarnCtx(ctx context.Context, msg string, fields ...Field) {
+	globalHelperLogger.WarnCtx(ctx, msg, fields...)
+}
+
+// ErrorCtx is the ErrorCtx method of a Logger that can be conveniently used in all packages.
+func ErrorCtx(ctx context.Context, msg string, fields ...Field) {
+	globalHelperLogger.ErrorCtx(ctx, msg, fields...)
+}
+
+// FatalCtx is the FatalCtx method of a Logger that can be conveniently used in all packages.
+func FatalCtx(ctx context.Context, msg string, fields ...Field) {
+	globalHelperLogger.FatalCtx(ctx, msg, fields...)
+}
+
+// PanicCtx is the PanicCtx method of a Logger that can be conveniently used in all packages.
+func PanicCtx(ctx context.Context, msg string, fields ...Field) {
+	globalHelperLogger.PanicCtx(ctx, msg, fields...)
 }
 
 // Flush is the Flush method of a Logger that can be conveniently used in all packages.
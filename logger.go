@@ -1,27 +1,54 @@
 package wlog
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"os"
+	"runtime"
 	"time"
 )
 
+// callerSkipOffset is the number of stack frames between the runtime.Caller
+// in the method write and the caller of a logging method such as Infow.
+const callerSkipOffset = 3
+
 // Logger contains all common data needed for logging and contains methods used to log messages.
 type Logger struct {
 	// minLvl is the minimum level allowed to log a message.
 	// It's default value is "DebugLvl".
-	minLvl Level
+	// It's shared by all Loggers derived from this Logger unless they replace it.
+	minLvl AtomicLevel
 	h      Handler
 	// errW is used to output the internal error when logging the message.
 	// os.Stderr is the default io writer.
 	errW io.Writer
+	// callerEnabled indicates whether to record the caller of logging methods.
+	callerEnabled bool
+	// callerSkip is the number of additional stack frames to skip when recording the caller.
+	callerSkip int
+	// stackEnabled indicates whether to record the stack trace for the log messages
+	// at or above the stackLvl.
+	stackEnabled bool
+	stackLvl     Level
+	// ctxExtractors are used to extract the fields from the context
+	// when logging a message by the methods such as InfoCtx.
+	ctxExtractors []ContextExtractor
 }
 
 type LoggerOpt func(l *Logger)
 
-// SetLogMinLvl sets the minimum logging level of the Logger
+// SetLogMinLvl sets the minimum logging level of the Logger.
+// It's independent of the minimum logging level of the Logger to be cloned.
 func SetLogMinLvl(lvl Level) LoggerOpt {
+	return func(l *Logger) {
+		l.minLvl = NewAtomicLevel(lvl)
+	}
+}
+
+// SetLogAtomicLvl sets the minimum logging level of the Logger to the given lvl,
+// so the level can be changed at runtime by calling lvl.Set(Level).
+func SetLogAtomicLvl(lvl AtomicLevel) LoggerOpt {
 	return func(l *Logger) {
 		l.minLvl = lvl
 	}
@@ -48,9 +75,34 @@ func SetLogErrW(errW io.Writer) LoggerOpt {
 	}
 }
 
+// EnableLogCaller enables the Logger to record the file, line number and function name
+// of the caller of logging methods.
+func EnableLogCaller() LoggerOpt {
+	return func(l *Logger) {
+		l.callerEnabled = true
+	}
+}
+
+// AddLogCallerSkip increases the number of stack frames to skip when recording the caller.
+// It's usually used by the libraries that wrap the Logger.
+func AddLogCallerSkip(skip int) LoggerOpt {
+	return func(l *Logger) {
+		l.callerSkip += skip
+	}
+}
+
+// EnableLogStack enables the Logger to record the stack trace for the log messages
+// at or above the given lvl.
+func EnableLogStack(lvl Level) LoggerOpt {
+	return func(l *Logger) {
+		l.stackEnabled = true
+		l.stackLvl = lvl
+	}
+}
+
 func NewLogger(h Handler, opts ...LoggerOpt) *Logger {
 	l := &Logger{
-		minLvl: DebugLvl,
+		minLvl: NewAtomicLevel(DebugLvl),
 		h:      h,
 		errW:   os.Stderr,
 	}
@@ -69,6 +121,57 @@ func (l *Logger) WithOpts(opts ...LoggerOpt) *Logger {
 	return clone
 }
 
+// AtomicLevel returns the minimum logging level of l, it can be used to
+// change the level of l and all Loggers derived from l at runtime.
+func (l *Logger) AtomicLevel() AtomicLevel {
+	return l.minLvl
+}
+
+// Enabled returns true if the logs at the given lvl would be written by l,
+// it checks both the minimum level of l and the Handlers that implement the CheckHandler.
+func (l *Logger) Enabled(lvl Level) bool {
+	return l.minLvl.Enabled(lvl) && handlerEnabled(l.h, lvl)
+}
+
+// Check returns a CheckedEntry if a log with the given lvl and msg would be written by l, otherwise nil.
+// It checks both the minimum level of l and the Handlers that implement the CheckHandler,
+// such as the SamplingHandler, so it's used to avoid constructing the expensive fields, such as:
+//
+//	if ce := logger.Check(DebugLvl, "request"); ce != nil {
+//		ce.Write(String("body", dump(req)))
+//	}
+//
+// Note that: it never returns nil at fatal-level and panic-level if the minimum level of l allows them,
+// so that the program always exits or panics after calling the Write.
+func (l *Logger) Check(lvl Level, msg string) *CheckedEntry {
+	if !l.minLvl.Enabled(lvl) {
+		return nil
+	}
+	if lvl < FatalLvl {
+		e := getEntry()
+		e.Set(lvl, msg)
+		ok := handlerCheck(l.h, e)
+		putEntry(e)
+		if !ok {
+			return nil
+		}
+	}
+	return &CheckedEntry{l: l, lvl: lvl, msg: msg}
+}
+
+// CheckedEntry is a log that has passed the check of a Logger, it's returned by the Check of the Logger.
+type CheckedEntry struct {
+	l   *Logger
+	lvl Level
+	msg string
+}
+
+// Write logs the checked message with the given fields.
+// The Handlers still decide whether to write it, such as the SamplingHandler counts it when writing.
+func (ce *CheckedEntry) Write(fields ...Field) {
+	ce.l.output(nil, ce.lvl, ce.msg, fields...)
+}
+
 // With returns a new Logger by cloning l, and then adds the given fields to it.
 func (l *Logger) With(fields ...Field) *Logger {
 	if len(fields) == 0 {
@@ -92,7 +195,7 @@ func (l *Logger) Withp(pairs ...interface{}) *Logger {
 // in most case, the first method may be more convenient to use, but if the debug-level is disabled,
 // the second method is more efficient.
 func (l *Logger) Debug(args ...interface{}) {
-	l.Debugw(fmt.Sprint(args...))
+	l.outputArgs(DebugLvl, args)
 }
 
 // Info logs a message to be constructed at info-level. It uses Sprint(...interface{}) to construct the args.
@@ -101,7 +204,7 @@ func (l *Logger) Debug(args ...interface{}) {
 // in most case, the first method may be more convenient to use, but if the info-level is disabled,
 // the second method is more efficient.
 func (l *Logger) Info(args ...interface{}) {
-	l.Infow(fmt.Sprint(args...))
+	l.outputArgs(InfoLvl, args)
 }
 
 // Warn logs a message to be constructed at warn-level. It uses Sprint(...interface{}) to construct the args.
@@ -110,7 +213,7 @@ func (l *Logger) Info(args ...interface{}) {
 // in most case, the first method may be more convenient to use, but if the warn-level is disabled,
 // the second method is more efficient.
 func (l *Logger) Warn(args ...interface{}) {
-	l.Warnw(fmt.Sprint(args...))
+	l.outputArgs(WarnLvl, args)
 }
 
 // Error logs a message to be constructed at error-level. It uses Sprint(...interface{}) to construct the args.
@@ -119,7 +222,7 @@ func (l *Logger) Warn(args ...interface{}) {
 // in most case, the first method may be more convenient to use, but if the error-level is disabled,
 // the second method is more efficient.
 func (l *Logger) Error(args ...interface{}) {
-	l.Errorw(fmt.Sprint(args...))
+	l.outputArgs(ErrorLvl, args)
 }
 
 // Fatal logs a message to be constructed at fatal-level. It uses Sprint(...interface{}) to construct the args.
@@ -128,7 +231,7 @@ func (l *Logger) Error(args ...interface{}) {
 // in most case, the first method may be more convenient to use, but if the fatal-level is disabled,
 // the second method is more efficient.
 func (l *Logger) Fatal(args ...interface{}) {
-	l.Fatalw(fmt.Sprint(args...))
+	l.outputArgs(FatalLvl, args)
 }
 
 // Panic logs a message to be constructed at panic-level. It uses Sprint(...interface{}) to construct the args.
@@ -137,109 +240,139 @@ func (l *Logger) Fatal(args ...interface{}) {
 // in most case, the first method may be more convenient to use, but if the panic-level is disabled,
 // the second method is more efficient.
 func (l *Logger) Panic(args ...interface{}) {
-	l.Panicw(fmt.Sprint(args...))
+	l.outputArgs(PanicLvl, args)
 }
 
 // Debugf logs a message to be formatted at debug-level.
 // It uses fmt.Sprintf(string, ...interface{}) to format the args.
 func (l *Logger) Debugf(format string, args ...interface{}) {
-	l.Debugw(fmt.Sprintf(format, args...))
+	l.outputFormat(DebugLvl, format, args)
 }
 
 // Infof logs a message at to be formatted info-level.
 // It uses fmt.Sprintf(string, ...interface{}) to format the args.
 func (l *Logger) Infof(format string, args ...interface{}) {
-	l.Infow(fmt.Sprintf(format, args...))
+	l.outputFormat(InfoLvl, format, args)
 }
 
 // Warnf logs a message to be formatted at warn-level.
 // It uses fmt.Sprintf(string, ...interface{}) to format the args.
 func (l *Logger) Warnf(format string, args ...interface{}) {
-	l.Warnw(fmt.Sprintf(format, args...))
+	l.outputFormat(WarnLvl, format, args)
 }
 
 // Error logs a message to be formatted at error-level.
 // It uses fmt.Sprintf(string, ...interface{}) to format the args.
 func (l *Logger) Errorf(format string, args ...interface{}) {
-	l.Errorw(fmt.Sprintf(format, args...))
+	l.outputFormat(ErrorLvl, format, args)
 }
 
 // Fatal logs a message at to be formatted fatal-level.
 // It uses fmt.Sprintf(string, ...interface{}) to format the args.
 func (l *Logger) Fatalf(format string, args ...interface{}) {
-	l.Fatalw(fmt.Sprintf(format, args...))
+	l.outputFormat(FatalLvl, format, args)
 }
 
 // Panic logs a message at to be formatted panic-level.
 // It uses fmt.Sprintf(string, ...interface{}) to format the args.
 func (l *Logger) Panicf(format string, args ...interface{}) {
-	l.Panicw(fmt.Sprintf(format, args...))
+	l.outputFormat(PanicLvl, format, args)
 }
 
 // Debugw logs a message at debug-level with any fields.
 func (l *Logger) Debugw(msg string, fields ...Field) {
-	l.output(DebugLvl, msg, fields...)
+	l.output(nil, DebugLvl, msg, fields...)
 }
 
 // Infow logs a message at info-level with any fields.
 func (l *Logger) Infow(msg string, fields ...Field) {
-	l.output(InfoLvl, msg, fields...)
+	l.output(nil, InfoLvl, msg, fields...)
 }
 
 // Warnw logs a message at warn-level with any fields.
 func (l *Logger) Warnw(msg string, fields ...Field) {
-	l.output(WarnLvl, msg, fields...)
+	l.output(nil, WarnLvl, msg, fields...)
 }
 
 // Errorw logs a message at error-level with any fields.
 func (l *Logger) Errorw(msg string, fields ...Field) {
-	l.output(ErrorLvl, msg, fields...)
+	l.output(nil, ErrorLvl, msg, fields...)
 }
 
 // Fatalw logs a message at fatal-level with any fields.
 func (l *Logger) Fatalw(msg string, fields ...Field) {
-	l.output(FatalLvl, msg, fields...)
+	l.output(nil, FatalLvl, msg, fields...)
 }
 
 // Panicw logs a message at panic-level with any fields.
 func (l *Logger) Panicw(msg string, fields ...Field) {
-	l.output(PanicLvl, msg, fields...)
+	l.output(nil, PanicLvl, msg, fields...)
 }
 
 // Debugp logs a message at debug-level with any key-value pairs.
 // In addition to key-value pairs, the pairs can also contains any independent fields of type *Field.
 func (l *Logger) Debugp(msg string, pairs ...interface{}) {
-	l.outputPairs(DebugLvl, msg, pairs...)
+	l.output(nil, DebugLvl, msg, pairsToFields(pairs...)...)
 }
 
 // Infop logs a message at info-level with any key-value pairs.
 // In addition to key-value pairs, the pairs can also contains any independent fields of type *Field.
 func (l *Logger) Infop(msg string, pairs ...interface{}) {
-	l.outputPairs(InfoLvl, msg, pairs...)
+	l.output(nil, InfoLvl, msg, pairsToFields(pairs...)...)
 }
 
 // Warnp logs a message at warn-level with any key-value pairs.
 // In addition to key-value pairs, the pairs can also contains any independent fields of type *Field.
 func (l *Logger) Warnp(msg string, pairs ...interface{}) {
-	l.outputPairs(WarnLvl, msg, pairs...)
+	l.output(nil, WarnLvl, msg, pairsToFields(pairs...)...)
 }
 
 // Errorp logs a message at error-level with any key-value pairs.
 // In addition to key-value pairs, the pairs can also contains any independent fields of type *Field.
 func (l *Logger) Errorp(msg string, pairs ...interface{}) {
-	l.outputPairs(ErrorLvl, msg, pairs...)
+	l.output(nil, ErrorLvl, msg, pairsToFields(pairs...)...)
 }
 
 // Fatalp logs a message at fatal-level with any key-value pairs.
 // In addition to key-value pairs, the pairs can also contains any independent fields of type *Field.
 func (l *Logger) Fatalp(msg string, pairs ...interface{}) {
-	l.outputPairs(FatalLvl, msg, pairs...)
+	l.output(nil, FatalLvl, msg, pairsToFields(pairs...)...)
 }
 
 // Panicp logs a message at panic-level with any key-value pairs.
 // In addition to key-value pairs, the pairs can also contains any independent fields of type *Field.
 func (l *Logger) Panicp(msg string, pairs ...interface{}) {
-	l.outputPairs(PanicLvl, msg, pairs...)
+	l.output(nil, PanicLvl, msg, pairsToFields(pairs...)...)
+}
+
+// DebugCtx logs a message at debug-level with the fields extracted from the ctx and any fields.
+func (l *Logger) DebugCtx(ctx context.Context, msg string, fields ...Field) {
+	l.output(ctx, DebugLvl, msg, fields...)
+}
+
+// InfoCtx logs a message at info-level with the fields extracted from the ctx and any fields.
+func (l *Logger) InfoCtx(ctx context.Context, msg string, fields ...Field) {
+	l.output(ctx, InfoLvl, msg, fields...)
+}
+
+// WarnCtx logs a message at warn-level with the fields extracted from the ctx and any fields.
+func (l *Logger) WarnCtx(ctx context.Context, msg string, fields ...Field) {
+	l.output(ctx, WarnLvl, msg, fields...)
+}
+
+// ErrorCtx logs a message at error-level with the fields extracted from the ctx and any fields.
+func (l *Logger) ErrorCtx(ctx context.Context, msg string, fields ...Field) {
+	l.output(ctx, ErrorLvl, msg, fields...)
+}
+
+// FatalCtx logs a message at fatal-level with the fields extracted from the ctx and any fields.
+func (l *Logger) FatalCtx(ctx context.Context, msg string, fields ...Field) {
+	l.output(ctx, FatalLvl, msg, fields...)
+}
+
+// PanicCtx logs a message at panic-level with the fields extracted from the ctx and any fields.
+func (l *Logger) PanicCtx(ctx context.Context, msg string, fields ...Field) {
+	l.output(ctx, PanicLvl, msg, fields...)
 }
 
 // Flush flushes any buffered logs to the disk.
@@ -255,12 +388,47 @@ func (l *Logger) Close() error {
 	return l.h.Close()
 }
 
-func (l *Logger) output(lvl Level, msg string, fields ...Field) {
-	if lvl < l.minLvl {
+// output logs the msg and fields at the given lvl,
+// if the given ctx is not nil, the fields extracted from it are logged before the fields.
+func (l *Logger) output(ctx context.Context, lvl Level, msg string, fields ...Field) {
+	if !l.minLvl.Enabled(lvl) {
+		return
+	}
+	l.write(ctx, lvl, msg, fields)
+}
+
+// outputArgs logs a message constructed by fmt.Sprint(args...) at the given lvl,
+// the message is constructed only if the lvl is enabled.
+func (l *Logger) outputArgs(lvl Level, args []interface{}) {
+	if !l.minLvl.Enabled(lvl) {
+		return
+	}
+	l.write(nil, lvl, fmt.Sprint(args...), nil)
+}
+
+// outputFormat logs a message formatted by fmt.Sprintf(format, args...) at the given lvl,
+// the message is formatted only if the lvl is enabled.
+func (l *Logger) outputFormat(lvl Level, format string, args []interface{}) {
+	if !l.minLvl.Enabled(lvl) {
 		return
 	}
+	l.write(nil, lvl, fmt.Sprintf(format, args...), nil)
+}
+
+// write logs the msg and fields at the given lvl regardless of the minimum level of l,
+// it must be called by the output methods directly to capture the caller correctly.
+func (l *Logger) write(ctx context.Context, lvl Level, msg string, fields []Field) {
 	e := getEntry()
 	e.Set(lvl, msg)
+	if l.callerEnabled {
+		e.Caller = NewEntryCaller(runtime.Caller(l.callerSkip + callerSkipOffset))
+	}
+	if l.stackEnabled && lvl >= l.stackLvl {
+		e.Stack = takeStacktrace()
+	}
+	if ctx != nil && len(l.ctxExtractors) > 0 {
+		fields = l.extractCtxFields(ctx, fields)
+	}
 	err := l.h.Write(e, fields...)
 	putEntry(e)
 	if err != nil {
@@ -279,22 +447,13 @@ func (l *Logger) output(lvl Level, msg string, fields ...Field) {
 	}
 }
 
-func (l *Logger) outputPairs(lvl Level, msg string, pairs ...interface{}) {
-	if len(pairs) == 0 {
-		l.output(lvl, msg)
-		return
-	}
-	fields := pairsToFields(pairs...)
-	l.output(lvl, msg, fields...)
-}
-
 func pairsToFields(pairs ...interface{}) []Field {
 	n := len(pairs)
-	// Allocate enough space for the worst case.
-	fields := make([]Field, 0, n)
 	if n == 0 {
-		return fields
+		return nil
 	}
+	// Allocate enough space for the worst case.
+	fields := make([]Field, 0, n)
 	var key, value interface{}
 	for i := 0; i < n; {
 		key = pairs[i]
@@ -8,6 +8,13 @@ import (
 	"io/ioutil"
 	"os"
 	"os/exec"
+	"bytes"
+	"runtime"
+	"strconv"
+	"encoding/json"
+	"context"
+	"strings"
+	"time"
 )
 
 func TestLogger(t *testing.T) {
@@ -93,3 +100,138 @@ func TestLoggerFatal(t *testing.T) {
 	}
 	t.Fatalf("process ran with err %v, want exit status 1", err)
 }
+
+func TestLoggerCaller(t *testing.T) {
+	buf := &bytes.Buffer{}
+	h := NewBaseHandler(NewIOWriter(buf), NewTextEncoder(DisableTime(), SetCallerEncoder(&FullCallerEncoder{})))
+	logger := NewLogger(h, EnableLogCaller())
+	_, file, line, _ := runtime.Caller(0)
+	logger.Infow("test logger")
+	logger.Info("test logger")
+	logger.Infop("test logger", "name", "xcj")
+	UseGlobalLogger(logger)
+	Infow("test logger")
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	assert.Len(t, lines, 4)
+	for i, l := range lines {
+		expected := file + ":" + strconv.Itoa(line+i+1)
+		if i == 3 {
+			expected = file + ":" + strconv.Itoa(line+i+2)
+		}
+		assert.Contains(t, string(l), expected)
+	}
+}
+
+func TestLoggerCallerSkip(t *testing.T) {
+	buf := &bytes.Buffer{}
+	h := NewBaseHandler(NewIOWriter(buf), NewJsonEncoder(DisableTime(), SetCallerEncoder(&FuncCallerEncoder{})))
+	logger := NewLogger(h, EnableLogCaller(), AddLogCallerSkip(1))
+	wrapper := func(msg string) {
+		logger.Infow(msg)
+	}
+	wrapper("test logger")
+	assert.Equal(t, `{"level":"info","caller":"github.com/happyxcj/wlog.TestLoggerCallerSkip","msg":"test logger"}`+"\n", buf.String())
+}
+
+func TestLoggerStack(t *testing.T) {
+	buf := &bytes.Buffer{}
+	h := NewBaseHandler(NewIOWriter(buf), NewJsonEncoder(DisableTime()))
+	logger := NewLogger(h, EnableLogStack(ErrorLvl))
+	logger.Warnw("test logger")
+	assert.Equal(t, `{"level":"warn","msg":"test logger"}`+"\n", buf.String())
+	buf.Reset()
+	logger.Errorw("test logger")
+	var m map[string]string
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &m))
+	assert.Contains(t, m[KeyStack], "testing.tRunner")
+	assert.NotContains(t, m[KeyStack], pkgPrefix)
+}
+
+func TestLoggerAtomicLevel(t *testing.T) {
+	buf := &bytes.Buffer{}
+	lvl := NewAtomicLevel(InfoLvl)
+	h := NewBaseHandler(NewIOWriter(buf), NewTextEncoder(DisableTime()))
+	logger := NewLogger(h, SetLogAtomicLvl(lvl))
+	child := logger.With(String("name", "xcj"))
+	child.Debugw("test logger")
+	assert.Equal(t, "", buf.String())
+	lvl.Set(DebugLvl)
+	child.Debugw("test logger")
+	assert.Equal(t, "[DEBUG]  test logger  [name=xcj]\n", buf.String())
+	assert.Equal(t, DebugLvl, logger.AtomicLevel().Level())
+}
+
+type requestIDKey struct{}
+
+func TestLoggerContext(t *testing.T) {
+	buf := &bytes.Buffer{}
+	extractor := func(ctx context.Context) []Field {
+		if id, ok := ctx.Value(requestIDKey{}).(string); ok {
+			return []Field{String("request_id", id)}
+		}
+		return nil
+	}
+	logger := NewLogger(newTestHandler(buf), AddLogContextExtractors(extractor))
+	ctx := context.WithValue(context.Background(), requestIDKey{}, "abc")
+	ctx = logger.WithContext(ctx)
+	FromContext(ctx).InfoCtx(ctx, "test logger", Int("age", 10))
+	FromContext(ctx).InfoCtx(context.Background(), "test logger")
+	assert.Equal(t, "[INFO]  test logger  [request_id=abc age=10]\n[INFO]  test logger\n", buf.String())
+	assert.Equal(t, globalLogger, FromContext(context.Background()))
+}
+
+func TestLoggerLazy(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := NewLogger(newTestHandler(buf), SetLogMinLvl(InfoLvl))
+	n := 0
+	counter := testCounter{n: &n}
+	lazy := Lazy("lazy", func() interface{} {
+		n++
+		return []int{1, 2}
+	})
+	lazyFields := LazyFields(func() []Field {
+		n++
+		return []Field{String("a", "b"), Stringer("counter", counter)}
+	})
+	logger.Debug("test logger ", counter)
+	logger.Debugf("test logger %v", counter)
+	logger.Debugw("test logger", lazy, lazyFields)
+	assert.Equal(t, 0, n)
+	assert.Equal(t, "", buf.String())
+
+	logger.Infof("test logger %v", counter)
+	logger.Infow("test logger", lazy, lazyFields)
+	assert.Equal(t, 4, n)
+	assert.Equal(t, "[INFO]  test logger counter\n[INFO]  test logger  [lazy=[1 2] a=b counter=counter]\n", buf.String())
+
+	buf.Reset()
+	logger.Infow("test logger", Lazy("lazy", func() interface{} { panic("boom") }))
+	assert.Equal(t, "[INFO]  test logger  [lazyError=panic when marshaling wlog.LazyVal: boom]\n", strings.SplitAfter(buf.String(), "\n")[0])
+}
+
+func TestLoggerCheck(t *testing.T) {
+	buf := &bytes.Buffer{}
+	h := NewSamplingHandler(NewLevelHandler(newTestHandler(buf), InfoLvl), time.Hour, 1, 0)
+	logger := NewLogger(h, SetLogMinLvl(DebugLvl), EnableLogCaller())
+	assert.False(t, logger.Enabled(DebugLvl))
+	assert.True(t, logger.Enabled(InfoLvl))
+	assert.Nil(t, logger.Check(DebugLvl, "test logger"))
+
+	child := logger.With(String("name", "xcj"))
+	ce := child.Check(InfoLvl, "test logger")
+	if assert.NotNil(t, ce) {
+		// Check doesn't consume the samples.
+		assert.NotNil(t, child.Check(InfoLvl, "test logger"))
+		_, file, line, _ := runtime.Caller(0)
+		ce.Write(Int("age", 10))
+		caller := NewEntryCaller(0, file, line+1, true).TrimmedPath()
+		assert.Equal(t, "[INFO]  "+caller+"  test logger  [name=xcj age=10]\n", buf.String())
+	}
+	// The message has been sampled in this tick.
+	assert.Nil(t, child.Check(InfoLvl, "test logger"))
+	assert.NotNil(t, child.Check(InfoLvl, "other"))
+	assert.NotNil(t, child.Check(FatalLvl, "test logger"))
+
+	assert.Nil(t, NewLogger(NewTeeHandler(h, NewLevelHandler(h, ErrorLvl))).Check(InfoLvl, "test logger"))
+	assert.True(t, NewLogger(NewTeeHandler(h, newTestHandler(buf))).Enabled(DebugLvl))
+}
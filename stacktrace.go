@@ -0,0 +1,54 @@
+package wlog
+
+import (
+	"reflect"
+	"runtime"
+	"strings"
+)
+
+// maxStackDepth is the maximum number of stack frames to record in a stack trace.
+const maxStackDepth = 64
+
+// pkgPrefix is the prefix of all function names in the wlog package,
+// such as "github.com/happyxcj/wlog.".
+var pkgPrefix = func() string {
+	name := runtime.FuncForPC(reflect.ValueOf(getEntry).Pointer()).Name()
+	return name[:strings.LastIndexByte(name, '.')+1]
+}()
+
+// takeStacktrace returns a string representation of the current goroutine's stack,
+// all leading frames inside the wlog package are trimmed.
+//
+// Every frame is formatted as "function\n\tfile:line", and all frames are separated by "\n".
+func takeStacktrace() string {
+	pcs := make([]uintptr, maxStackDepth)
+	// Skip runtime.Callers and takeStacktrace.
+	n := runtime.Callers(2, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+	buf := GetBuf()
+	trimming := true
+	for {
+		frame, more := frames.Next()
+		if trimming && strings.HasPrefix(frame.Function, pkgPrefix) {
+			if !more {
+				break
+			}
+			continue
+		}
+		trimming = false
+		if buf.Len() > 0 {
+			buf.AppendByte('\n')
+		}
+		buf.AppendString(frame.Function)
+		buf.AppendString("\n\t")
+		buf.AppendString(frame.File)
+		buf.AppendByte(':')
+		buf.AppendInt(frame.Line)
+		if !more {
+			break
+		}
+	}
+	stack := buf.String()
+	PutBuf(buf)
+	return stack
+}
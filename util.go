@@ -1,5 +1,7 @@
 package wlog
 
+import "unsafe"
+
 const lowerDigit = "0123456789abcdef"
 
 // uin64ToHex returns the hexadecimal value of the given v.
@@ -21,3 +23,9 @@ func uin64ToHex(v uint64) []byte {
 	buf[i] = '0'
 	return buf[i:]
 }
+
+// bytesToStr converts the bs to a string without copying,
+// the bs must not be modified while the string is in use.
+func bytesToStr(bs []byte) string {
+	return *(*string)(unsafe.Pointer(&bs))
+}
@@ -160,6 +160,10 @@ func (w *BufWriter) Close() error {
 		w.flushBuf()
 	}
 	w.isClosed = true
+	// Wake up the "writeLoop" to exit if it's waiting for the buffered data.
+	if w.condWaiting {
+		w.cond.Signal()
+	}
 	w.mu.Unlock()
 	w.wg.Wait()
 	return w.Writer.Close()
@@ -186,7 +190,7 @@ func (w *BufWriter) writeLoop() {
 	newNextBuf := w.makeBuffer()
 	for {
 		w.mu.Lock()
-		if len(w.buffers) <= 0 {
+		for len(w.buffers) <= 0 {
 			// In order to write all buffered data to underlying writer after "Close" method is called.
 			// do not exit the loop even if it is detected that the w has been closed when the "buf" is not empty.
 			if w.isClosed {
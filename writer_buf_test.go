@@ -0,0 +1,44 @@
+package wlog
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBufWriterClose(t *testing.T) {
+	// Close must return even if the BufWriter never got any data.
+	w := NewBufWriter(NewIOWriter(&bytes.Buffer{}))
+	waitBufWriterIdle(w)
+	done := make(chan error, 1)
+	go func() { done <- w.Close() }()
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("Close of an unwritten BufWriter does not return")
+	}
+
+	buf := &bytes.Buffer{}
+	w = NewBufWriter(NewIOWriter(buf))
+	w.Write([]byte("hello"))
+	assert.NoError(t, w.Close())
+	assert.Equal(t, "hello", buf.String())
+	_, err := w.Write([]byte("closed"))
+	assert.Error(t, err)
+}
+
+// waitBufWriterIdle waits until the writeLoop of the w is waiting for the buffered data.
+func waitBufWriterIdle(w *BufWriter) {
+	for {
+		w.mu.Lock()
+		waiting := w.condWaiting
+		w.mu.Unlock()
+		if waiting {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+}